@@ -0,0 +1,189 @@
+package cron
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// AdminHandler 返回管理任务的 http.Handler，响应均为 JSON，可以挂在任意前缀下，比如
+// http.Handle("/cron/", http.StripPrefix("/cron", cron.AdminHandler(c)))
+//
+//	GET    /jobs              所有任务，见 ListJobs
+//	GET    /jobs/{id}         单个任务
+//	GET    /jobs/{id}/history 执行记录，见 History
+//	POST   /jobs/{id}/run     立即触发一次，见 CallAsync
+//	POST   /jobs/{id}/pause   暂停，见 PauseJob
+//	POST   /jobs/{id}/resume  恢复，见 ResumeJob
+//	DELETE /jobs/{id}         删除，见 RemoveJob
+//	GET    /healthz           健康状况，见 Healthz，不健康时状态码为 503
+//
+// 本身不做鉴权，暴露到公网之前需要在外层加上认证
+func AdminHandler(c *Cron) http.Handler {
+	return &admin{c: c}
+}
+
+type admin struct {
+	c *Cron
+}
+
+// adminJob 任务的 JSON 表示
+type adminJob struct {
+	ID           int         `json:"id"`
+	Name         string      `json:"name,omitempty"`
+	Group        string      `json:"group,omitempty"`
+	Status       string      `json:"status"`
+	Spec         string      `json:"spec"`
+	Description  string      `json:"description"`
+	Next         time.Time   `json:"next"`
+	Prev         time.Time   `json:"prev"`
+	LastRun      time.Time   `json:"last_run"`
+	LastDuration string      `json:"last_duration,omitempty"`
+	LastError    string      `json:"last_error,omitempty"`
+	Runs         uint64      `json:"runs"`
+	Payload      interface{} `json:"payload,omitempty"`
+}
+
+// adminRun 执行记录的 JSON 表示
+type adminRun struct {
+	Start      time.Time `json:"start"`
+	Duration   string    `json:"duration"`
+	Outcome    Outcome   `json:"outcome"`
+	Error      string    `json:"error,omitempty"`
+	SkipReason string    `json:"skip_reason,omitempty"`
+}
+
+func statusName(status uint) string {
+	switch status {
+	case StatusRunning:
+		return "running"
+	case StatusPaused:
+		return "paused"
+	default:
+		return "ready"
+	}
+}
+
+func (a *admin) job(info JobInfo) adminJob {
+	j := adminJob{
+		ID:          info.ID,
+		Name:        info.Name,
+		Group:       info.Group,
+		Status:      statusName(info.Status),
+		Spec:        info.Spec,
+		Description: a.c.Describe(info.ID),
+		Next:        info.Next,
+		Prev:        info.Prev,
+		LastRun:     info.LastRun,
+		Runs:        info.Runs,
+		Payload:     info.Payload,
+	}
+	if !info.LastRun.IsZero() {
+		j.LastDuration = info.LastDuration.String()
+	}
+	if info.LastError != nil {
+		j.LastError = info.LastError.Error()
+	}
+	return j
+}
+
+func (a *admin) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) == 1 && parts[0] == "healthz" {
+		a.healthz(w, r)
+		return
+	}
+	if len(parts) == 0 || parts[0] != "jobs" || len(parts) > 3 {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+	if len(parts) == 1 {
+		if r.Method != http.MethodGet {
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		infos := a.c.ListJobs()
+		jobs := make([]adminJob, 0, len(infos))
+		for _, info := range infos {
+			jobs = append(jobs, a.job(info))
+		}
+		writeJSON(w, http.StatusOK, jobs)
+		return
+	}
+
+	id, err := strconv.Atoi(parts[1])
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid job id %q", parts[1]))
+		return
+	}
+	info, ok := a.c.jobInfo(id)
+	if !ok {
+		writeError(w, http.StatusNotFound, ErrNotFound.Error())
+		return
+	}
+	action := ""
+	if len(parts) == 3 {
+		action = parts[2]
+	}
+
+	switch {
+	case action == "" && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, a.job(info))
+	case action == "" && r.Method == http.MethodDelete:
+		a.c.RemoveJob(id)
+		w.WriteHeader(http.StatusNoContent)
+	case action == "history" && r.Method == http.MethodGet:
+		records := a.c.History(id, 0)
+		runs := make([]adminRun, 0, len(records))
+		for _, rec := range records {
+			runs = append(runs, adminRun{
+				Start:      rec.Start,
+				Duration:   rec.Duration.String(),
+				Outcome:    rec.Outcome,
+				Error:      rec.Error,
+				SkipReason: rec.SkipReason,
+			})
+		}
+		writeJSON(w, http.StatusOK, runs)
+	case action == "run" && r.Method == http.MethodPost:
+		writeJSON(w, http.StatusAccepted, map[string]bool{"started": a.c.CallAsync(id)})
+	case action == "pause" && r.Method == http.MethodPost:
+		a.c.PauseJob(id)
+		info, _ = a.c.jobInfo(id)
+		writeJSON(w, http.StatusOK, a.job(info))
+	case action == "resume" && r.Method == http.MethodPost:
+		a.c.ResumeJob(id)
+		info, _ = a.c.jobInfo(id)
+		writeJSON(w, http.StatusOK, a.job(info))
+	case action == "" || action == "history" || action == "run" || action == "pause" || action == "resume":
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	default:
+		writeError(w, http.StatusNotFound, "not found")
+	}
+}
+
+func (a *admin) healthz(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	report := a.c.Healthz()
+	code := http.StatusOK
+	if !report.Healthy {
+		code = http.StatusServiceUnavailable
+	}
+	writeJSON(w, code, report)
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, code int, msg string) {
+	writeJSON(w, code, map[string]string{"error": msg})
+}
@@ -0,0 +1,169 @@
+package cron
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// serve 向 AdminHandler 发送请求，返回状态码，body 不为 nil 时解码响应
+func serve(t *testing.T, h http.Handler, method, path string, body interface{}) int {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(method, path, nil))
+	if body != nil {
+		if err := json.NewDecoder(rec.Body).Decode(body); err != nil {
+			t.Fatalf("%s %s: decode: %v", method, path, err)
+		}
+	}
+	if ct := rec.Header().Get("Content-Type"); rec.Code != http.StatusNoContent && ct != "application/json" {
+		t.Errorf("%s %s: Content-Type %q", method, path, ct)
+	}
+	return rec.Code
+}
+
+func TestAdminListAndGet(t *testing.T) {
+	c := NewCron(WithLogger(DiscardLogger))
+	a := c.AddJob("0 0 9 * * *", func() {}, WithName("report"), WithGroup("daily"))
+	b := c.AddJob("0 30 10 * * *", func() {})
+	h := AdminHandler(c)
+
+	var jobs []adminJob
+	if code := serve(t, h, http.MethodGet, "/jobs", &jobs); code != http.StatusOK {
+		t.Fatalf("list: %d", code)
+	}
+	if len(jobs) != 2 || jobs[0].ID != a || jobs[1].ID != b {
+		t.Fatalf("list = %+v", jobs)
+	}
+	if jobs[0].Name != "report" || jobs[0].Group != "daily" || jobs[0].Status != "ready" || jobs[0].Spec != "0 0 9 * * *" {
+		t.Errorf("job = %+v", jobs[0])
+	}
+
+	var job adminJob
+	if code := serve(t, h, http.MethodGet, fmt.Sprintf("/jobs/%d", b), &job); code != http.StatusOK || job.ID != b {
+		t.Errorf("get: %d, %+v", code, job)
+	}
+	if job.Description == "" || job.Next.IsZero() {
+		t.Errorf("get: missing description or next run: %+v", job)
+	}
+}
+
+func TestAdminPauseResume(t *testing.T) {
+	c := NewCron(WithLogger(DiscardLogger))
+	id := c.AddJob("0 0 9 * * *", func() {})
+	h := AdminHandler(c)
+
+	var job adminJob
+	if code := serve(t, h, http.MethodPost, fmt.Sprintf("/jobs/%d/pause", id), &job); code != http.StatusOK || job.Status != "paused" {
+		t.Fatalf("pause: %d, %+v", code, job)
+	}
+	if c.GetStatus(id) != StatusPaused {
+		t.Error("job not paused")
+	}
+	if code := serve(t, h, http.MethodPost, fmt.Sprintf("/jobs/%d/resume", id), &job); code != http.StatusOK || job.Status != "ready" {
+		t.Fatalf("resume: %d, %+v", code, job)
+	}
+	if c.GetStatus(id) != StatusReady {
+		t.Error("job not resumed")
+	}
+}
+
+func TestAdminRunAndHistory(t *testing.T) {
+	c := NewCron(WithLogger(DiscardLogger))
+	c.Start()
+	defer c.Stop()
+	ran := make(chan struct{}, 1)
+	id := c.AddJob("0 0 9 * * *", func() { ran <- struct{}{} }, WithHistorySize(4))
+	h := AdminHandler(c)
+
+	var started map[string]bool
+	if code := serve(t, h, http.MethodPost, fmt.Sprintf("/jobs/%d/run", id), &started); code != http.StatusAccepted || !started["started"] {
+		t.Fatalf("run: %d, %v", code, started)
+	}
+	receive(t, ran)
+	waitStats(t, c, id, func(st JobStats) bool { return st.Successes == 1 })
+
+	var runs []adminRun
+	if code := serve(t, h, http.MethodGet, fmt.Sprintf("/jobs/%d/history", id), &runs); code != http.StatusOK {
+		t.Fatalf("history: %d", code)
+	}
+	if len(runs) != 1 || runs[0].Outcome != OutcomeSuccess {
+		t.Errorf("history = %+v", runs)
+	}
+}
+
+func TestAdminDelete(t *testing.T) {
+	c := NewCron(WithLogger(DiscardLogger))
+	id := c.AddJob("0 0 9 * * *", func() {})
+	h := AdminHandler(c)
+
+	if code := serve(t, h, http.MethodDelete, fmt.Sprintf("/jobs/%d", id), nil); code != http.StatusNoContent {
+		t.Fatalf("delete: %d", code)
+	}
+	if c.Count() != 0 {
+		t.Error("job not removed")
+	}
+}
+
+func TestAdminErrors(t *testing.T) {
+	c := NewCron(WithLogger(DiscardLogger))
+	id := c.AddJob("0 0 9 * * *", func() {})
+	h := AdminHandler(c)
+	job := fmt.Sprintf("/jobs/%d", id)
+
+	tests := []struct {
+		method, path string
+		code         int
+	}{
+		{http.MethodGet, "/jobs/999", http.StatusNotFound},
+		{http.MethodPost, "/jobs/999/run", http.StatusNotFound},
+		{http.MethodGet, "/jobs/abc", http.StatusBadRequest},
+		{http.MethodGet, "/unknown", http.StatusNotFound},
+		{http.MethodGet, job + "/unknown", http.StatusNotFound},
+		{http.MethodGet, job + "/run/extra", http.StatusNotFound},
+		{http.MethodPost, "/jobs", http.StatusMethodNotAllowed},
+		{http.MethodPost, job, http.StatusMethodNotAllowed},
+		{http.MethodGet, job + "/run", http.StatusMethodNotAllowed},
+		{http.MethodGet, job + "/pause", http.StatusMethodNotAllowed},
+		{http.MethodDelete, job + "/resume", http.StatusMethodNotAllowed},
+		{http.MethodPost, job + "/history", http.StatusMethodNotAllowed},
+		{http.MethodPost, "/healthz", http.StatusMethodNotAllowed},
+	}
+	for _, tt := range tests {
+		var body map[string]string
+		if code := serve(t, h, tt.method, tt.path, &body); code != tt.code || body["error"] == "" {
+			t.Errorf("%s %s: %d %v, want %d with an error", tt.method, tt.path, code, body, tt.code)
+		}
+	}
+	if c.GetStatus(id) != StatusReady || c.Count() != 1 {
+		t.Error("a rejected request changed the job")
+	}
+}
+
+func TestAdminHealthz(t *testing.T) {
+	c := NewCron(WithLogger(DiscardLogger))
+	h := AdminHandler(c)
+	var report HealthReport
+	code := serve(t, h, http.MethodGet, "/healthz", &report)
+	if report.Healthy && code != http.StatusOK || !report.Healthy && code != http.StatusServiceUnavailable {
+		t.Errorf("healthz: %d with %+v", code, report)
+	}
+
+	c.Start()
+	defer c.Stop()
+	if code := serve(t, h, http.MethodGet, "/healthz", &report); code != http.StatusOK || !report.Healthy {
+		t.Errorf("running scheduler: %d, %+v", code, report)
+	}
+}
+
+func TestAdminUnderPrefix(t *testing.T) {
+	c := NewCron(WithLogger(DiscardLogger))
+	c.AddJob("0 0 9 * * *", func() {})
+	h := http.StripPrefix("/cron", AdminHandler(c))
+	var jobs []adminJob
+	if code := serve(t, h, http.MethodGet, "/cron/jobs/", &jobs); code != http.StatusOK || len(jobs) != 1 {
+		t.Errorf("prefixed list: %d, %+v", code, jobs)
+	}
+}
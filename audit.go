@@ -0,0 +1,145 @@
+package cron
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// AuditRecord 一次执行的审计记录，重试的每一次尝试各有一条
+type AuditRecord struct {
+	// ID 任务 ID
+	ID int `json:"id"`
+	// Name 任务名，见 WithName
+	Name string `json:"name,omitempty"`
+	// Source 触发来源，为 Source* 常量之一，重试时为 SourceRetry
+	Source string `json:"source"`
+	// Attempt 第几次尝试，从 1 开始
+	Attempt int `json:"attempt"`
+	// Start、End 开始和结束的时间
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+	// Outcome 执行结果，被跳过的触发没有审计记录
+	Outcome Outcome `json:"outcome"`
+	// Error 错误信息，成功时为空
+	Error string `json:"error,omitempty"`
+}
+
+// AuditWriter 接收每次执行的审计记录，在执行结束时同步调用，实现需要保证并发安全
+// 返回的错误会记录到日志，不影响任务本身
+type AuditWriter interface {
+	WriteAudit(rec AuditRecord) error
+}
+
+type _AuditWriter struct {
+	AuditWriter
+}
+
+func (w _AuditWriter) apply(opts *options) {
+	opts.AuditWriters = append(opts.AuditWriters, w.AuditWriter)
+}
+
+func (w _AuditWriter) applyCron(opts *cronOptions) {
+	opts.AuditWriters = append(opts.AuditWriters, w.AuditWriter)
+}
+
+// WithAuditWriter 为每次执行写入审计记录，传给 NewCron 时作用于所有任务，添加任务时传入只作用于该任务
+func WithAuditWriter(w AuditWriter) CommonOption {
+	return _AuditWriter{w}
+}
+
+// sourceKey 在 ctx 中保存本次执行的触发来源
+type sourceKey struct{}
+
+// audit 写入一次尝试的审计记录，recovered 为最后一次尝试中未被转换的 panic
+func (s *Cron) audit(ctx context.Context, id int, opt options, attempt int, start time.Time, err error, recovered interface{}) {
+	if len(s.audits) == 0 && len(opt.AuditWriters) == 0 {
+		return
+	}
+	end := time.Now()
+	source, _ := ctx.Value(sourceKey{}).(string)
+	if attempt > 1 {
+		source = SourceRetry
+	}
+	if recovered != nil {
+		err = &PanicError{Value: recovered}
+	}
+	var p *PanicError
+	rec := AuditRecord{
+		ID:      id,
+		Name:    opt.Name,
+		Source:  source,
+		Attempt: attempt,
+		Start:   start,
+		End:     end,
+		Outcome: outcome(err, errors.As(err, &p), opt.Timeout > 0 && end.Sub(start) > opt.Timeout),
+	}
+	if err != nil {
+		rec.Error = err.Error()
+	}
+
+	for _, w := range append(append([]AuditWriter(nil), s.audits...), opt.AuditWriters...) {
+		if err := w.WriteAudit(rec); err != nil {
+			l, kv := s.jobLogger(id)
+			l.Error(err, "write audit record failed", kv...)
+		}
+	}
+}
+
+// JSONLinesWriter 将审计记录以 JSON Lines 格式写入 io.Writer，每条记录一行
+type JSONLinesWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewJSONLinesWriter 创建写入 w 的 JSONLinesWriter
+func NewJSONLinesWriter(w io.Writer) *JSONLinesWriter {
+	return &JSONLinesWriter{w: w}
+}
+
+func (j *JSONLinesWriter) WriteAudit(rec AuditRecord) error {
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	_, err = j.w.Write(append(data, '\n'))
+	return err
+}
+
+// AuditFile 以 JSON Lines 格式追加写入文件的 AuditWriter，每条记录写入后都会 Sync，保证进程崩溃时不丢失
+type AuditFile struct {
+	mu sync.Mutex
+	f  *os.File
+	w  *JSONLinesWriter
+}
+
+// OpenAuditFile 以追加方式打开 path，文件不存在时创建
+func OpenAuditFile(path string) (*AuditFile, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return &AuditFile{f: f, w: NewJSONLinesWriter(f)}, nil
+}
+
+func (a *AuditFile) WriteAudit(rec AuditRecord) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if err := a.w.WriteAudit(rec); err != nil {
+		return err
+	}
+	return a.f.Sync()
+}
+
+// Close 关闭文件，之后的写入会返回错误
+func (a *AuditFile) Close() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.f.Close()
+}
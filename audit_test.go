@@ -0,0 +1,160 @@
+package cron
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// auditRecorder 保存收到的审计记录，err 不为 nil 时写入失败
+type auditRecorder struct {
+	mu   sync.Mutex
+	recs []AuditRecord
+	err  error
+}
+
+func (r *auditRecorder) WriteAudit(rec AuditRecord) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.recs = append(r.recs, rec)
+	return r.err
+}
+
+func (r *auditRecorder) get() []AuditRecord {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]AuditRecord(nil), r.recs...)
+}
+
+func TestAuditRecordsEveryAttempt(t *testing.T) {
+	global, local := &auditRecorder{}, &auditRecorder{}
+	c := NewCron(WithAuditWriter(global), WithLogger(DiscardLogger))
+	calls := 0
+	id := c.AddJobE2("0 0 9 * * *", func() error {
+		calls++
+		if calls == 1 {
+			return errors.New("boom")
+		}
+		return nil
+	}, WithName("report"), WithRetry(1, 0), WithAuditWriter(local))
+	before := time.Now()
+	c.Call(id)
+
+	recs := global.get()
+	if len(recs) != 2 {
+		t.Fatalf("records = %+v, want one per attempt", recs)
+	}
+	first, retry := recs[0], recs[1]
+	if first.ID != id || first.Name != "report" || first.Source != SourceManual || first.Attempt != 1 ||
+		first.Outcome != OutcomeError || first.Error != "boom" {
+		t.Errorf("first attempt = %+v", first)
+	}
+	if retry.Source != SourceRetry || retry.Attempt != 2 || retry.Outcome != OutcomeSuccess || retry.Error != "" {
+		t.Errorf("retry = %+v", retry)
+	}
+	if first.Start.Before(before) || first.End.Before(first.Start) || retry.Start.Before(first.End) {
+		t.Errorf("times out of order: %+v", recs)
+	}
+	// 添加任务时传入的 writer 只收到该任务的记录
+	other := c.AddJob("0 0 9 * * *", func() {})
+	c.Call(other)
+	if n := len(local.get()); n != 2 {
+		t.Errorf("job writer got %d records, want 2", n)
+	}
+	if recs := global.get(); len(recs) != 3 || recs[2].ID != other || recs[2].Outcome != OutcomeSuccess {
+		t.Errorf("global writer got %+v", recs)
+	}
+}
+
+func TestAuditPanicAndSkip(t *testing.T) {
+	w := &auditRecorder{}
+	c := NewCron(WithAuditWriter(w), WithLogger(DiscardLogger))
+	id := c.AddJob("0 0 9 * * *", func() { panic("boom") }, WithRateLimit(rate.Every(time.Hour), 1))
+	c.Call(id)
+	// 被跳过的触发没有审计记录
+	c.Call(id)
+
+	recs := w.get()
+	if len(recs) != 1 || recs[0].Outcome != OutcomePanic || !strings.Contains(recs[0].Error, "boom") {
+		t.Errorf("records = %+v, want one panic", recs)
+	}
+}
+
+func TestAuditWriterErrorIsLogged(t *testing.T) {
+	logger := &errorLogger{}
+	w := &auditRecorder{err: errors.New("disk full")}
+	c := NewCron(WithAuditWriter(w), WithLogger(logger))
+	ran := false
+	id := c.AddJob("0 0 9 * * *", func() { ran = true })
+	c.Call(id)
+	if !ran || atomic.LoadInt32(&logger.errors) != 1 {
+		t.Errorf("ran %v, %d errors logged", ran, logger.errors)
+	}
+	if st, _ := c.Stats(id); st.Successes != 1 {
+		t.Errorf("a failed audit write changed the outcome: %+v", st)
+	}
+}
+
+func TestJSONLinesWriter(t *testing.T) {
+	var buf bytes.Buffer
+	c := NewCron(WithAuditWriter(NewJSONLinesWriter(&buf)), WithLogger(DiscardLogger))
+	id := c.AddJobE2("0 0 9 * * *", func() error { return errors.New("boom") }, WithName("report"))
+	c.Call(id)
+	c.Call(id)
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("%d lines: %q", len(lines), buf.String())
+	}
+	var rec AuditRecord
+	if err := json.Unmarshal([]byte(lines[0]), &rec); err != nil {
+		t.Fatal(err)
+	}
+	if rec.ID != id || rec.Name != "report" || rec.Outcome != OutcomeError || rec.Error != "boom" || rec.Start.IsZero() {
+		t.Errorf("decoded %+v", rec)
+	}
+}
+
+func TestAuditFileAppends(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	for i := 0; i < 2; i++ {
+		f, err := OpenAuditFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		c := NewCron(WithAuditWriter(f), WithLogger(DiscardLogger))
+		c.Call(c.AddJob("0 0 9 * * *", func() {}))
+		if err := f.Close(); err != nil {
+			t.Fatal(err)
+		}
+		if err := f.WriteAudit(AuditRecord{}); err == nil {
+			t.Error("write after Close succeeded")
+		}
+	}
+
+	data, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer data.Close()
+	n := 0
+	for sc := bufio.NewScanner(data); sc.Scan(); n++ {
+		var rec AuditRecord
+		if err := json.Unmarshal(sc.Bytes(), &rec); err != nil || rec.Outcome != OutcomeSuccess {
+			t.Errorf("line %d: %+v, %v", n, rec, err)
+		}
+	}
+	if n != 2 {
+		t.Errorf("%d records, want one from each run", n)
+	}
+}
@@ -0,0 +1,264 @@
+package cron
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 字段下标，与六段式 spec 的顺序一致
+const (
+	fieldSecond = iota
+	fieldMinute
+	fieldHour
+	fieldDom
+	fieldMonth
+	fieldDow
+)
+
+// SpecBuilder 以链式调用构造 spec，避免手写六段式字符串，比如
+//
+//	cron.Every(5).Minutes().At(cron.Second(30)).Spec() // "30 */5 * * * *"
+//	cron.Daily().At(cron.Hour(9), cron.Minute(30)).Spec() // "0 30 9 * * *"
+//	cron.Weekly(time.Monday, time.Friday).At(cron.Hour(18)).Spec() // "0 0 18 * * 1,5"
+//
+// 间隔能整除所在字段时生成 */N，否则改用 @every，保证相邻两次执行的间隔始终相同；
+// 用法错误（比如值越界、At 指定了不小于周期的字段）在 Spec 时返回
+type SpecBuilder struct {
+	fields [6]string
+	// unit 周期所在的字段，At 只能设置比它小的字段
+	unit int
+	// every 不为空时使用 @every，不能再用 At 和 On
+	every time.Duration
+	err   error
+}
+
+// Interval 由 Every 创建，选择单位后得到 SpecBuilder
+type Interval struct {
+	n int
+}
+
+// Every 每隔 n 个单位执行一次，单位由后续的 Seconds、Minutes 等方法决定
+func Every(n int) Interval {
+	return Interval{n: n}
+}
+
+// Seconds 每隔 n 秒
+func (i Interval) Seconds() *SpecBuilder {
+	return i.build(fieldSecond, 60, time.Second)
+}
+
+// Minutes 每隔 n 分钟，默认在第 0 秒
+func (i Interval) Minutes() *SpecBuilder {
+	return i.build(fieldMinute, 60, time.Minute)
+}
+
+// Hours 每隔 n 小时，默认在整点
+func (i Interval) Hours() *SpecBuilder {
+	return i.build(fieldHour, 24, time.Hour)
+}
+
+// Days 每隔 n 天，默认在 00:00:00；n 大于 1 时使用 @every，不能再用 At
+func (i Interval) Days() *SpecBuilder {
+	if i.n == 1 {
+		return i.build(fieldDom, 1, 24*time.Hour)
+	}
+	return i.build(fieldDom, 0, 24*time.Hour)
+}
+
+// Months 每隔 n 个月，默认在 1 号 00:00:00，n 需要整除 12
+func (i Interval) Months() *SpecBuilder {
+	b := i.build(fieldMonth, 12, 0)
+	b.fields[fieldDom] = "1"
+	return b
+}
+
+// build 生成周期为 n 个 unit 的 SpecBuilder，span 为字段的范围，不能整除时使用 @every
+func (i Interval) build(unit, span int, d time.Duration) *SpecBuilder {
+	b := &SpecBuilder{unit: unit}
+	for f := range b.fields {
+		switch {
+		case f < unit:
+			b.fields[f] = "0"
+		default:
+			b.fields[f] = "*"
+		}
+	}
+	switch {
+	case i.n <= 0:
+		b.err = ErrInvalidInterval
+	case i.n == 1:
+	case span > 0 && span%i.n == 0:
+		b.fields[unit] = "*/" + strconv.Itoa(i.n)
+	case d > 0:
+		b.every = time.Duration(i.n) * d
+	default:
+		b.err = fmt.Errorf("cron: every %d months does not divide a year", i.n)
+	}
+	return b
+}
+
+// Daily 每天 00:00:00 执行，等同于 Every(1).Days()
+func Daily() *SpecBuilder {
+	return Every(1).Days()
+}
+
+// Weekly 每周的 days 执行，默认在 00:00:00
+func Weekly(days ...time.Weekday) *SpecBuilder {
+	return Daily().On(days...)
+}
+
+// Monthly 每月的 day 号执行，默认在 00:00:00，day 为 1-31，没有这一天的月份不会执行
+func Monthly(day int) *SpecBuilder {
+	return Every(1).Months().OnDay(day)
+}
+
+// TimeField At 使用的时刻字段，由 Second、Minute、Hour 创建
+type TimeField struct {
+	field int
+	value int
+}
+
+// Second 第 n 秒，0-59
+func Second(n int) TimeField {
+	return TimeField{field: fieldSecond, value: n}
+}
+
+// Minute 第 n 分钟，0-59
+func Minute(n int) TimeField {
+	return TimeField{field: fieldMinute, value: n}
+}
+
+// Hour 第 n 小时，0-23
+func Hour(n int) TimeField {
+	return TimeField{field: fieldHour, value: n}
+}
+
+// At 设置执行的时刻，只能设置比周期小的字段，比如 Every(1).Hours() 可以设置分和秒
+func (b *SpecBuilder) At(fields ...TimeField) *SpecBuilder {
+	for _, f := range fields {
+		span := specFields[f.field].span
+		switch {
+		case b.every > 0:
+			b.fail(errors.New("cron: At cannot be used with an @every interval"))
+		case f.field >= b.unit:
+			b.fail(fmt.Errorf("cron: %s is not smaller than the interval", specFields[f.field].name))
+		case f.value < 0 || f.value >= span:
+			b.fail(fmt.Errorf("cron: %s %d out of range [0, %d)", specFields[f.field].name, f.value, span))
+		default:
+			b.fields[f.field] = strconv.Itoa(f.value)
+		}
+	}
+	return b
+}
+
+// On 只在 days 执行，可以与 Every(n).Hours() 等组合，比如工作日每小时执行
+func (b *SpecBuilder) On(days ...time.Weekday) *SpecBuilder {
+	if b.every > 0 {
+		return b.fail(errors.New("cron: On cannot be used with an @every interval"))
+	}
+	if len(days) == 0 {
+		return b
+	}
+	parts := make([]string, 0, len(days))
+	for _, d := range days {
+		if d < time.Sunday || d > time.Saturday {
+			return b.fail(fmt.Errorf("cron: invalid weekday %d", d))
+		}
+		parts = append(parts, strconv.Itoa(int(d)))
+	}
+	b.fields[fieldDow] = strings.Join(parts, ",")
+	return b
+}
+
+// OnDay 只在每月的 day 号执行，day 为 1-31
+func (b *SpecBuilder) OnDay(day int) *SpecBuilder {
+	if b.every > 0 {
+		return b.fail(errors.New("cron: OnDay cannot be used with an @every interval"))
+	}
+	if day < 1 || day > 31 {
+		return b.fail(fmt.Errorf("cron: day-of-month %d out of range [1, 31]", day))
+	}
+	b.fields[fieldDom] = strconv.Itoa(day)
+	return b
+}
+
+// fail 记录第一个错误
+func (b *SpecBuilder) fail(err error) *SpecBuilder {
+	if b.err == nil {
+		b.err = err
+	}
+	return b
+}
+
+// Spec 返回六段式 spec 或 @every 描述符，构造过程中出错时返回第一个错误
+func (b *SpecBuilder) Spec() (string, error) {
+	if b.err != nil {
+		return "", b.err
+	}
+	if b.every > 0 {
+		return fmt.Sprintf("@every %v", b.every), nil
+	}
+	return strings.Join(b.fields[:], " "), nil
+}
+
+// AddScheduleJob 按 SpecBuilder 生成的 spec 添加任务，会按 WithoutSeconds 转换为五段式
+// 使用 WithoutSeconds 时秒字段不为 0 返回 ErrSecondsDisabled
+func (s *Cron) AddScheduleJob(b *SpecBuilder, f func(), options ...Option) (id int, err error) {
+	spec, err := b.Spec()
+	if err != nil {
+		return -1, err
+	}
+	if !strings.HasPrefix(spec, "@") {
+		if !s.seconds && b.fields[fieldSecond] != "0" {
+			return -1, ErrSecondsDisabled
+		}
+		spec = s.adapt(spec)
+	}
+	return s.AddJobE(spec, f, options...)
+}
+
+// TimeOfDay 一天中的时刻，用于 AddDailyJob 等方法，按调度器的时区理解
+type TimeOfDay struct {
+	Hour   int
+	Minute int
+	Second int
+}
+
+// ParseTimeOfDay 解析 "15:04" 或 "15:04:05" 格式的时刻
+func ParseTimeOfDay(s string) (TimeOfDay, error) {
+	for _, layout := range []string{"15:04:05", "15:04"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return TimeOfDay{Hour: t.Hour(), Minute: t.Minute(), Second: t.Second()}, nil
+		}
+	}
+	return TimeOfDay{}, fmt.Errorf("cron: invalid time of day %q", s)
+}
+
+func (t TimeOfDay) String() string {
+	return fmt.Sprintf("%02d:%02d:%02d", t.Hour, t.Minute, t.Second)
+}
+
+// fields 转换为 At 使用的字段，越界的值在 Spec 时报错
+func (t TimeOfDay) fields() []TimeField {
+	return []TimeField{Hour(t.Hour), Minute(t.Minute), Second(t.Second)}
+}
+
+// AddDailyJob 添加每天 at 执行的任务，at 越界时返回错误
+func (s *Cron) AddDailyJob(at TimeOfDay, f func(), options ...Option) (id int, err error) {
+	return s.AddScheduleJob(Daily().At(at.fields()...), f, options...)
+}
+
+// AddWeeklyJob 添加每周 weekday 的 at 执行的任务，weekday 或 at 越界时返回错误
+// 与 AddWeekJob 不同，这里直接使用 time.Weekday，不需要换算
+func (s *Cron) AddWeeklyJob(weekday time.Weekday, at TimeOfDay, f func(), options ...Option) (id int, err error) {
+	return s.AddScheduleJob(Weekly(weekday).At(at.fields()...), f, options...)
+}
+
+// AddMonthlyJob 添加每月 dayOfMonth 号的 at 执行的任务，dayOfMonth 为 1-31，没有这一天的月份不会执行
+// dayOfMonth 或 at 越界时返回错误
+func (s *Cron) AddMonthlyJob(dayOfMonth int, at TimeOfDay, f func(), options ...Option) (id int, err error) {
+	return s.AddScheduleJob(Monthly(dayOfMonth).At(at.fields()...), f, options...)
+}
@@ -0,0 +1,167 @@
+package cron
+
+import (
+	"testing"
+	"time"
+)
+
+// nextFires 用 robfig 解析器解析 spec，返回 from 之后的 n 次触发时间
+func nextFires(t *testing.T, spec string, from time.Time, n int) []time.Time {
+	t.Helper()
+	sched, err := secondParser.Parse(spec)
+	if err != nil {
+		t.Fatalf("parse %q: %v", spec, err)
+	}
+	out := make([]time.Time, 0, n)
+	for i := 0; i < n; i++ {
+		from = sched.Next(from)
+		out = append(out, from)
+	}
+	return out
+}
+
+func date(year int, month time.Month, day, hour, min, sec int) time.Time {
+	return time.Date(year, month, day, hour, min, sec, 0, time.UTC)
+}
+
+func TestCalendarJobsFireTimes(t *testing.T) {
+	noop := func() {}
+	at := TimeOfDay{Hour: 9, Minute: 30, Second: 15}
+	// 2024-01-01 是星期一
+	from := date(2024, time.January, 1, 12, 0, 0)
+	tests := []struct {
+		name string
+		add  func(c *Cron) (int, error)
+		spec string
+		want []time.Time
+	}{
+		{"daily", func(c *Cron) (int, error) { return c.AddDailyJob(at, noop) }, "15 30 9 * * *", []time.Time{
+			date(2024, time.January, 2, 9, 30, 15),
+			date(2024, time.January, 3, 9, 30, 15),
+			date(2024, time.January, 4, 9, 30, 15),
+		}},
+		{"daily midnight", func(c *Cron) (int, error) { return c.AddDailyJob(TimeOfDay{}, noop) }, "0 0 0 * * *", []time.Time{
+			date(2024, time.January, 2, 0, 0, 0),
+			date(2024, time.January, 3, 0, 0, 0),
+		}},
+		{"weekly", func(c *Cron) (int, error) { return c.AddWeeklyJob(time.Friday, at, noop) }, "15 30 9 * * 5", []time.Time{
+			date(2024, time.January, 5, 9, 30, 15),
+			date(2024, time.January, 12, 9, 30, 15),
+			date(2024, time.January, 19, 9, 30, 15),
+		}},
+		{"weekly sunday", func(c *Cron) (int, error) { return c.AddWeeklyJob(time.Sunday, at, noop) }, "15 30 9 * * 0", []time.Time{
+			date(2024, time.January, 7, 9, 30, 15),
+			date(2024, time.January, 14, 9, 30, 15),
+		}},
+		{"monthly", func(c *Cron) (int, error) { return c.AddMonthlyJob(15, at, noop) }, "15 30 9 15 * *", []time.Time{
+			date(2024, time.January, 15, 9, 30, 15),
+			date(2024, time.February, 15, 9, 30, 15),
+			date(2024, time.March, 15, 9, 30, 15),
+		}},
+		// 没有 31 号的月份直接跳过，不会挪到月末
+		{"monthly day 31", func(c *Cron) (int, error) { return c.AddMonthlyJob(31, at, noop) }, "15 30 9 31 * *", []time.Time{
+			date(2024, time.January, 31, 9, 30, 15),
+			date(2024, time.March, 31, 9, 30, 15),
+			date(2024, time.May, 31, 9, 30, 15),
+			date(2024, time.July, 31, 9, 30, 15),
+			date(2024, time.August, 31, 9, 30, 15),
+		}},
+		// 闰年才有 2 月 29 号
+		{"monthly day 29", func(c *Cron) (int, error) { return c.AddMonthlyJob(29, at, noop) }, "15 30 9 29 * *", []time.Time{
+			date(2024, time.January, 29, 9, 30, 15),
+			date(2024, time.February, 29, 9, 30, 15),
+			date(2024, time.March, 29, 9, 30, 15),
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewCron(WithLocation(time.UTC), WithLogger(DiscardLogger))
+			id, err := tt.add(c)
+			if err != nil {
+				t.Fatalf("add: %v", err)
+			}
+			info, ok := c.jobInfo(id)
+			if !ok {
+				t.Fatalf("job %d not registered", id)
+			}
+			if info.Spec != tt.spec {
+				t.Errorf("spec = %q, want %q", info.Spec, tt.spec)
+			}
+			got := nextFires(t, info.Spec, from, len(tt.want))
+			for i := range tt.want {
+				if !got[i].Equal(tt.want[i]) {
+					t.Errorf("fire %d = %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestCalendarJobsRejectOutOfRange(t *testing.T) {
+	noop := func() {}
+	valid := TimeOfDay{Hour: 9}
+	tests := []struct {
+		name string
+		add  func(c *Cron) (int, error)
+	}{
+		{"hour 24", func(c *Cron) (int, error) { return c.AddDailyJob(TimeOfDay{Hour: 24}, noop) }},
+		{"negative hour", func(c *Cron) (int, error) { return c.AddDailyJob(TimeOfDay{Hour: -1}, noop) }},
+		{"minute 60", func(c *Cron) (int, error) { return c.AddWeeklyJob(time.Monday, TimeOfDay{Minute: 60}, noop) }},
+		{"second 60", func(c *Cron) (int, error) { return c.AddMonthlyJob(1, TimeOfDay{Second: 60}, noop) }},
+		{"weekday 7", func(c *Cron) (int, error) { return c.AddWeeklyJob(time.Weekday(7), valid, noop) }},
+		{"negative weekday", func(c *Cron) (int, error) { return c.AddWeeklyJob(time.Weekday(-1), valid, noop) }},
+		{"day 0", func(c *Cron) (int, error) { return c.AddMonthlyJob(0, valid, noop) }},
+		{"day 32", func(c *Cron) (int, error) { return c.AddMonthlyJob(32, valid, noop) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewCron(WithLogger(DiscardLogger))
+			id, err := tt.add(c)
+			if err == nil || id != -1 {
+				t.Errorf("got id %d, err %v; want -1 and an error", id, err)
+			}
+			if jobs := c.ListJobs(); len(jobs) != 0 {
+				t.Errorf("rejected job was registered: %+v", jobs)
+			}
+		})
+	}
+}
+
+func TestCalendarJobsWithoutSeconds(t *testing.T) {
+	noop := func() {}
+	c := NewCron(WithoutSeconds(), WithLocation(time.UTC), WithLogger(DiscardLogger))
+	if _, err := c.AddDailyJob(TimeOfDay{Hour: 9, Second: 1}, noop); err != ErrSecondsDisabled {
+		t.Errorf("second without seconds: err = %v, want ErrSecondsDisabled", err)
+	}
+	id, err := c.AddMonthlyJob(31, TimeOfDay{Hour: 9, Minute: 30}, noop)
+	if err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	next, ok := c.NextRun(id)
+	if !ok {
+		t.Fatal("no next run")
+	}
+	if next.Day() != 31 || next.Hour() != 9 || next.Minute() != 30 || next.Second() != 0 {
+		t.Errorf("next run = %v, want the 31st at 09:30:00", next)
+	}
+}
+
+func TestParseTimeOfDay(t *testing.T) {
+	tests := []struct {
+		in   string
+		want TimeOfDay
+		ok   bool
+	}{
+		{"09:30", TimeOfDay{Hour: 9, Minute: 30}, true},
+		{"23:59:59", TimeOfDay{Hour: 23, Minute: 59, Second: 59}, true},
+		{"24:00", TimeOfDay{}, false},
+		{"12:60", TimeOfDay{}, false},
+		{"9am", TimeOfDay{}, false},
+	}
+	for _, tt := range tests {
+		got, err := ParseTimeOfDay(tt.in)
+		if (err == nil) != tt.ok || got != tt.want {
+			t.Errorf("ParseTimeOfDay(%q) = %v, %v", tt.in, got, err)
+		}
+	}
+}
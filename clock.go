@@ -0,0 +1,305 @@
+package cron
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// Clock 调度器使用的时钟，见 WithClock
+type Clock interface {
+	// Now 返回当前时间
+	Now() time.Time
+	// NewTimer 创建在 d 之后触发的定时器
+	NewTimer(d time.Duration) Timer
+}
+
+// Timer Clock 创建的定时器
+type Timer interface {
+	// C 定时器触发时收到当时的时间
+	C() <-chan time.Time
+	// Stop 停止定时器，定时器已经触发或已经停止时返回 false
+	Stop() bool
+}
+
+// systemClock 使用系统时间的 Clock
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+func (systemClock) NewTimer(d time.Duration) Timer { return systemTimer{time.NewTimer(d)} }
+
+type systemTimer struct {
+	t *time.Timer
+}
+
+func (t systemTimer) C() <-chan time.Time { return t.t.C }
+
+func (t systemTimer) Stop() bool { return t.t.Stop() }
+
+type _Clock struct {
+	Clock
+}
+
+func (c _Clock) applyCron(opts *cronOptions) {
+	opts.Clock = c.Clock
+}
+
+// WithClock 使用 c 决定定时触发的时机，配合 FakeClock 可以在测试中推进虚拟时间，不需要真正等待
+// 设置后定时触发不再由 robfig 的调度循环负责，而是由调度器按 c 的时间计算，NextRun、PrevRun、Entry 等同样以 c 为准；
+// 执行耗时、超时、重试间隔等仍使用系统时间
+func WithClock(c Clock) CronOption {
+	return _Clock{c}
+}
+
+// FakeClock 手动推进的 Clock，用于测试，时间只在调用 Advance 时变化
+type FakeClock struct {
+	mu     sync.Mutex
+	cond   *sync.Cond
+	now    time.Time
+	timers []*fakeTimer
+}
+
+// NewFakeClock 创建当前时间为 now 的 FakeClock
+func NewFakeClock(now time.Time) *FakeClock {
+	c := &FakeClock{now: now}
+	c.cond = sync.NewCond(&c.mu)
+	return c
+}
+
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *FakeClock) NewTimer(d time.Duration) Timer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	t := &fakeTimer{c: c, at: c.now.Add(d), ch: make(chan time.Time, 1)}
+	if d <= 0 {
+		t.ch <- c.now
+		return t
+	}
+	c.timers = append(c.timers, t)
+	c.cond.Broadcast()
+	return t
+}
+
+// Advance 将时间推进 d 并触发到期的定时器
+// 一次推进跨过某个调度的多次触发时只触发一次，与进程暂停后恢复时的行为相同，需要逐次触发时请分步推进；
+// 任务在各自的 goroutine 中执行，Advance 返回时任务不一定已经开始，可以配合 BlockUntil 等待调度器处理完
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	pending := c.timers[:0]
+	for _, t := range c.timers {
+		if t.at.After(c.now) {
+			pending = append(pending, t)
+			continue
+		}
+		t.ch <- c.now
+	}
+	c.timers = pending
+	c.cond.Broadcast()
+}
+
+// BlockUntil 阻塞到至少有 n 个尚未触发的定时器
+// 调度器运行期间始终等待在下一次触发上，Advance 之后 BlockUntil(1) 返回时说明到期的任务都已经触发
+func (c *FakeClock) BlockUntil(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for len(c.timers) < n {
+		c.cond.Wait()
+	}
+}
+
+type fakeTimer struct {
+	c  *FakeClock
+	at time.Time
+	ch chan time.Time
+}
+
+func (t *fakeTimer) C() <-chan time.Time { return t.ch }
+
+func (t *fakeTimer) Stop() bool {
+	t.c.mu.Lock()
+	defer t.c.mu.Unlock()
+	for i, other := range t.c.timers {
+		if other == t {
+			t.c.timers = append(t.c.timers[:i], t.c.timers[i+1:]...)
+			t.c.cond.Broadcast()
+			return true
+		}
+	}
+	return false
+}
+
+// driver 设置 WithClock 时代替 robfig 的调度循环，记录每个 robfig Entry 的 Next 和 Prev
+type driver struct {
+	mu   sync.Mutex
+	next map[cron.EntryID]time.Time
+	prev map[cron.EntryID]time.Time
+	wake chan struct{}
+}
+
+func newDriver() *driver {
+	return &driver{
+		next: make(map[cron.EntryID]time.Time),
+		prev: make(map[cron.EntryID]time.Time),
+		wake: make(chan struct{}, 1),
+	}
+}
+
+// notify 调度发生变化，让调度循环重新计算
+func (d *driver) notify() {
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+}
+
+// entryTimes 返回 robfig Entry 的 Next 和 Prev，调度器尚未启动时 Next 为零值
+func (s *Cron) entryTimes(id cron.EntryID) (next, prev time.Time) {
+	if s.driver == nil {
+		entry := s.c.Entry(id)
+		return entry.Next, entry.Prev
+	}
+	s.driver.mu.Lock()
+	defer s.driver.mu.Unlock()
+	return s.driver.next[id], s.driver.prev[id]
+}
+
+// drive 按 Clock 触发任务，done 关闭时退出
+func (s *Cron) drive(done <-chan struct{}) {
+	d := s.driver
+	// 与 robfig 一样，每次 Start 都重新计算所有 Next
+	d.mu.Lock()
+	d.next = make(map[cron.EntryID]time.Time)
+	d.mu.Unlock()
+	for {
+		var (
+			timer Timer
+			fired <-chan time.Time
+		)
+		if next := s.tick(); !next.IsZero() {
+			timer = s.clock.NewTimer(next.Sub(s.clock.Now()))
+			fired = timer.C()
+		}
+		select {
+		case <-fired:
+		case <-d.wake:
+		case <-done:
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+		select {
+		case <-done:
+			return
+		default:
+		}
+	}
+}
+
+// tick 触发所有到期的调度，返回最早的下一次触发时间，没有时返回零值
+func (s *Cron) tick() time.Time {
+	type firing struct {
+		id int
+		at time.Time
+	}
+	var (
+		d        = s.driver
+		now      = s.now()
+		earliest time.Time
+		fires    []firing
+		seen     = make(map[cron.EntryID]bool)
+	)
+	s.lock.RLock()
+	d.mu.Lock()
+	s.entry.Range(func(_, value interface{}) bool {
+		e := value.(*entry)
+		for i, entryID := range e.ids {
+			seen[entryID] = true
+			next, ok := d.next[entryID]
+			if !ok {
+				next = e.scheds[i].Next(now)
+			}
+			if !next.IsZero() && !next.After(now) {
+				fires = append(fires, firing{id: e.id, at: next})
+				d.prev[entryID] = next
+				next = e.scheds[i].Next(now)
+			}
+			d.next[entryID] = next
+			if !next.IsZero() && (earliest.IsZero() || next.Before(earliest)) {
+				earliest = next
+			}
+		}
+		return true
+	})
+	for entryID := range d.next {
+		if !seen[entryID] {
+			delete(d.next, entryID)
+			delete(d.prev, entryID)
+		}
+	}
+	d.mu.Unlock()
+	s.lock.RUnlock()
+
+	sort.Slice(fires, func(i, j int) bool {
+		if !fires[i].at.Equal(fires[j].at) {
+			return fires[i].at.Before(fires[j].at)
+		}
+		return fires[i].id < fires[j].id
+	})
+	for _, f := range fires {
+		go s.execute(f.id, trigger{source: SourceSchedule, at: f.at})
+	}
+	return earliest
+}
+
+// Firing DryRun 返回的一次触发
+type Firing struct {
+	// Time 触发时间，位于调度器的时区
+	Time time.Time
+	// ID 任务 ID
+	ID int
+}
+
+// DryRun 计算 (from, to] 内所有任务的触发，按时间排序，同一时刻按 id 排序，不执行任何任务
+// 暂停的任务不计入；单个调度最多计算 maxLoadFires 次，与 EstimatedLoad 相同
+func (s *Cron) DryRun(from, to time.Time) []Firing {
+	var out []Firing
+	// 调度按调度器的时区计算，from 和 to 需要先转换过去
+	from, to = from.In(s.location), to.In(s.location)
+	s.lock.RLock()
+	s.entry.Range(func(_, value interface{}) bool {
+		e := value.(*entry)
+		if e.paused {
+			return true
+		}
+		for _, sched := range e.scheds {
+			t := from
+			for n := 0; n < maxLoadFires; n++ {
+				t = peekNext(sched, t)
+				if t.IsZero() || t.After(to) {
+					break
+				}
+				out = append(out, Firing{Time: t.In(s.location), ID: e.id})
+			}
+		}
+		return true
+	})
+	s.lock.RUnlock()
+
+	sort.Slice(out, func(i, j int) bool {
+		if !out[i].Time.Equal(out[j].Time) {
+			return out[i].Time.Before(out[j].Time)
+		}
+		return out[i].ID < out[j].ID
+	})
+	return out
+}
@@ -0,0 +1,128 @@
+package cron
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestFakeClockTimers(t *testing.T) {
+	clk := NewFakeClock(testStart)
+	a := clk.NewTimer(time.Second)
+	b := clk.NewTimer(3 * time.Second)
+	clk.BlockUntil(2)
+
+	clk.Advance(time.Second)
+	if at := receive(t, a.C()); !at.Equal(testStart.Add(time.Second)) {
+		t.Fatalf("timer fired at %v", at)
+	}
+	never(t, b.C())
+	if a.Stop() {
+		t.Error("Stop on a fired timer returned true")
+	}
+	if !b.Stop() {
+		t.Error("Stop on a pending timer returned false")
+	}
+	clk.Advance(time.Hour)
+	never(t, b.C())
+
+	if now := clk.Now(); !now.Equal(testStart.Add(time.Hour + time.Second)) {
+		t.Errorf("Now() = %v", now)
+	}
+	receive(t, clk.NewTimer(0).C())
+}
+
+func TestFakeClockFiresJobs(t *testing.T) {
+	c, clk := newFakeCron(t)
+	var (
+		mu    sync.Mutex
+		fired []time.Time
+	)
+	ran := make(chan struct{}, 10)
+	id := c.AddJob("*/2 * * * * *", func() {
+		mu.Lock()
+		fired = append(fired, clk.Now())
+		mu.Unlock()
+		ran <- struct{}{}
+	})
+	c.Start()
+
+	for i := 0; i < 6; i++ {
+		blockUntil(t, clk, 1)
+		clk.Advance(time.Second)
+		if clk.Now().Second()%2 == 0 {
+			receive(t, ran)
+		} else {
+			never(t, ran)
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	// testStart 为 55 秒，任务在 56、58、00 秒触发
+	want := []time.Time{testStart.Add(time.Second), testStart.Add(3 * time.Second), testStart.Add(5 * time.Second)}
+	if !reflect.DeepEqual(fired, want) {
+		t.Fatalf("fired at %v, want %v", fired, want)
+	}
+	if prev, _ := c.PrevRun(id); !prev.Equal(testStart.Add(5 * time.Second)) {
+		t.Errorf("PrevRun = %v", prev)
+	}
+	if next, _ := c.NextRun(id); !next.Equal(testStart.Add(7 * time.Second)) {
+		t.Errorf("NextRun = %v", next)
+	}
+}
+
+func TestFakeClockPausedJobDoesNotFire(t *testing.T) {
+	c, clk := newFakeCron(t)
+	ran := make(chan struct{}, 10)
+	id := c.AddSecondJob(1, func() { ran <- struct{}{} })
+	c.Start()
+	c.PauseJob(id)
+
+	clk.Advance(3 * time.Second)
+	never(t, ran)
+
+	c.ResumeJob(id)
+	blockUntil(t, clk, 1)
+	clk.Advance(time.Second)
+	receive(t, ran)
+}
+
+func TestDryRun(t *testing.T) {
+	c, _ := newFakeCron(t)
+	a := c.AddJob("0 0 9 * * *", func() {})
+	b := c.AddJob("*/30 * * * * *", func() {})
+	paused := c.AddJob("* * * * * *", func() {})
+	c.PauseJob(paused)
+
+	got := c.DryRun(testStart, testStart.Add(time.Minute))
+	nine := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	want := []Firing{
+		{Time: nine, ID: a},
+		{Time: nine, ID: b},
+		{Time: nine.Add(30 * time.Second), ID: b},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("DryRun = %v, want %v", got, want)
+	}
+	if got := c.DryRun(nine, nine); len(got) != 0 {
+		t.Errorf("DryRun of an empty window = %v", got)
+	}
+}
+
+func TestDryRunUsesSchedulerLocation(t *testing.T) {
+	shanghai := time.FixedZone("CST", 8*3600)
+	c := NewCron(WithLocation(shanghai), WithLogger(DiscardLogger))
+	id := c.AddJob("0 0 9 * * *", func() {})
+
+	// 2024-01-01 00:00 UTC 为北京时间 08:00，9 点的任务在 01:00 UTC 触发
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	got := c.DryRun(from, from.Add(2*time.Hour))
+	if len(got) != 1 || got[0].ID != id {
+		t.Fatalf("DryRun = %v", got)
+	}
+	if want := time.Date(2024, 1, 1, 9, 0, 0, 0, shanghai); !got[0].Time.Equal(want) || got[0].Time.Hour() != 9 {
+		t.Errorf("fired at %v, want %v", got[0].Time, want)
+	}
+}
@@ -0,0 +1,223 @@
+package cron
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ErrNoName 配置中的任务或需要持久化的任务没有设置任务名
+var ErrNoName = errors.New("cron: job without name")
+
+// JobConfig 声明式配置中的一个任务，按 Name 与已有任务对应
+// 任务函数无法写在配置中，Func 为 RegisterFunc 注册时使用的函数名
+type JobConfig struct {
+	// Name 任务名，必须设置且不能重复
+	Name string `json:"name" yaml:"name"`
+	// Spec 任务的 spec
+	Spec string `json:"spec" yaml:"spec"`
+	// Func 任务函数注册时的名字
+	Func string `json:"func" yaml:"func"`
+	// Group 见 WithGroup
+	Group string `json:"group,omitempty" yaml:"group,omitempty"`
+	// RunMode 运行模式
+	RunMode RunMode `json:"run_mode,omitempty" yaml:"run_mode,omitempty"`
+	// Timeout 见 WithTimeout，JSON 中为纳秒数，YAML 中可以写成 30s 这样的字符串
+	Timeout time.Duration `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+}
+
+// options 转换为添加任务时的配置
+func (cfg JobConfig) options() []Option {
+	return []Option{WithName(cfg.Name), WithGroup(cfg.Group), WithRunMode(cfg.RunMode), WithTimeout(cfg.Timeout)}
+}
+
+// configured 通过 LoadFromConfig 添加的任务
+type configured struct {
+	mu   sync.Mutex
+	jobs map[string]JobConfig
+}
+
+// LoadFromConfig 让调度器中由配置管理的任务与 cfg 一致：
+// 新出现的任务会被添加，配置变化的任务原地替换，id、统计信息和执行记录保持不变，
+// 上一次配置中有而 cfg 中没有的任务会被删除；没有经过 LoadFromConfig 添加的任务不受影响，
+// 除非与配置中的任务同名，此时会被配置中的任务替换并从此由配置管理
+// 配置无效（没有任务名、函数未注册、spec 无效）的任务会被跳过，其余任务照常处理，返回遇到的第一个错误
+func (s *Cron) LoadFromConfig(cfg []JobConfig) error {
+	s.configured.mu.Lock()
+	defer s.configured.mu.Unlock()
+
+	var first error
+	report := func(err error, c JobConfig) {
+		s.logger.Error(err, "load job config failed", "name", c.Name, "spec", c.Spec)
+		if first == nil {
+			first = err
+		}
+	}
+
+	desired := make(map[string]bool, len(cfg))
+	for _, c := range cfg {
+		if c.Name == "" {
+			report(ErrNoName, c)
+			continue
+		}
+		if desired[c.Name] {
+			report(fmt.Errorf("%w %q", ErrDuplicateName, c.Name), c)
+			continue
+		}
+		desired[c.Name] = true
+
+		if old, ok := s.configured.jobs[c.Name]; ok && old == c {
+			if _, exists := s.lookup(c.Name); exists {
+				continue
+			}
+		}
+		f, ok := s.lookupFunc(c.Func)
+		if !ok {
+			report(fmt.Errorf("%w: %s", ErrUnknownFunc, c.Func), c)
+			continue
+		}
+		if _, err := s.AddJobE(c.Spec, f, c.options()...); err != nil {
+			report(err, c)
+			continue
+		}
+		s.configured.jobs[c.Name] = c
+	}
+
+	for name := range s.configured.jobs {
+		if !desired[name] {
+			s.RemoveJobByName(name)
+			delete(s.configured.jobs, name)
+		}
+	}
+	return first
+}
+
+// ConfigSource 提供声明式配置，见 Watch
+type ConfigSource interface {
+	// Next 阻塞到配置发生变化，返回完整的任务列表，第一次调用返回当前的配置
+	// ctx 结束时返回 ctx.Err()，不会再有新的配置时返回 io.EOF
+	Next(ctx context.Context) ([]JobConfig, error)
+}
+
+// Watch 持续从 source 读取配置并通过 LoadFromConfig 应用，阻塞到 ctx 结束或 source 返回 io.EOF
+// source 返回其他错误或配置应用失败时记录到日志并继续等待下一次配置
+// ctx 结束时返回 ctx.Err()，source 结束时返回 nil
+func (s *Cron) Watch(ctx context.Context, source ConfigSource) error {
+	for {
+		cfg, err := source.Next(ctx)
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			s.logger.Error(err, "read job config failed")
+			continue
+		}
+		_ = s.LoadFromConfig(cfg)
+	}
+}
+
+// ChanSource 从 channel 读取配置的 ConfigSource，channel 关闭后 Watch 返回
+type ChanSource <-chan []JobConfig
+
+func (ch ChanSource) Next(ctx context.Context) ([]JobConfig, error) {
+	select {
+	case cfg, ok := <-ch:
+		if !ok {
+			return nil, io.EOF
+		}
+		return cfg, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// FileSource 从文件读取配置的 ConfigSource，按扩展名 .yaml、.yml 解析为 YAML，其余解析为 JSON
+// 文件内容为 JobConfig 的列表；每隔一段时间检查一次文件，内容变化时返回新的配置
+type FileSource struct {
+	path     string
+	interval time.Duration
+	read     bool
+	last     []byte
+	lastErr  string
+}
+
+// NewFileSource 创建每隔 interval 检查一次 path 的 FileSource，interval 不大于 0 时为 5 秒
+func NewFileSource(path string, interval time.Duration) *FileSource {
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+	return &FileSource{path: path, interval: interval}
+}
+
+// Next 第一次调用立即读取文件，之后等到文件内容变化；同一个读取或解析错误只返回一次
+func (f *FileSource) Next(ctx context.Context) ([]JobConfig, error) {
+	if !f.read {
+		f.read = true
+		return f.load()
+	}
+
+	ticker := time.NewTicker(f.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		cfg, err := f.load()
+		if err == nil && cfg == nil {
+			continue
+		}
+		return cfg, err
+	}
+}
+
+// load 读取并解析文件，内容和错误都没有变化时返回 nil, nil
+func (f *FileSource) load() ([]JobConfig, error) {
+	data, err := os.ReadFile(f.path)
+	if err != nil {
+		return f.fail(err)
+	}
+	if f.lastErr == "" && f.last != nil && bytes.Equal(data, f.last) {
+		return nil, nil
+	}
+
+	var cfg []JobConfig
+	switch strings.ToLower(filepath.Ext(f.path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &cfg)
+	default:
+		err = json.Unmarshal(data, &cfg)
+	}
+	if err != nil {
+		f.last = data
+		return f.fail(fmt.Errorf("cron: decode %s: %w", f.path, err))
+	}
+	f.last, f.lastErr = data, ""
+	if cfg == nil {
+		cfg = []JobConfig{}
+	}
+	return cfg, nil
+}
+
+// fail 返回错误，与上一次的错误相同时返回 nil, nil
+func (f *FileSource) fail(err error) ([]JobConfig, error) {
+	if err.Error() == f.lastErr {
+		return nil, nil
+	}
+	f.lastErr = err.Error()
+	return nil, err
+}
@@ -0,0 +1,233 @@
+package cron
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newConfigCron(t *testing.T) (*Cron, chan string) {
+	t.Helper()
+	c := NewCron(WithLogger(DiscardLogger))
+	ran := make(chan string, 8)
+	c.RegisterFunc("report", func() { ran <- "report" })
+	c.RegisterFunc("cleanup", func() { ran <- "cleanup" })
+	return c, ran
+}
+
+func TestLoadFromConfigDiff(t *testing.T) {
+	c, ran := newConfigCron(t)
+	if err := c.LoadFromConfig([]JobConfig{
+		{Name: "a", Spec: "0 0 9 * * *", Func: "report"},
+		{Name: "b", Spec: "0 0 10 * * *", Func: "cleanup", Timeout: time.Minute},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	a, _ := c.GetJobByName("a")
+	b, _ := c.GetJobByName("b")
+	hashA, hashB := c.ScheduleHash(a.ID), c.ScheduleHash(b.ID)
+	c.Call(a.ID)
+	receive(t, ran)
+
+	// a 不变，b 的配置变化，c 新增
+	if err := c.LoadFromConfig([]JobConfig{
+		{Name: "a", Spec: "0 0 9 * * *", Func: "report"},
+		{Name: "b", Spec: "0 30 10 * * *", Func: "report", Timeout: time.Minute},
+		{Name: "c", Spec: "0 0 11 * * *", Func: "cleanup"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := c.GetJobByName("a"); got.ID != a.ID || got.Runs != 1 || c.ScheduleHash(a.ID) != hashA {
+		t.Errorf("unchanged job was touched: %+v", got)
+	}
+	got, _ := c.GetJobByName("b")
+	if got.ID != b.ID || got.Spec != "0 30 10 * * *" || c.ScheduleHash(b.ID) == hashB {
+		t.Errorf("changed job not replaced in place: %+v", got)
+	}
+	c.Call(b.ID)
+	if f := receive(t, ran); f != "report" {
+		t.Errorf("replaced job ran %s", f)
+	}
+	if _, ok := c.GetJobByName("c"); !ok {
+		t.Error("new job not added")
+	}
+
+	// 配置中不再出现的任务被删除，其他任务不受影响
+	manual := c.AddJob("0 0 12 * * *", func() {})
+	if err := c.LoadFromConfig([]JobConfig{{Name: "c", Spec: "0 0 11 * * *", Func: "cleanup"}}); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"a", "b"} {
+		if _, ok := c.GetJobByName(name); ok {
+			t.Errorf("%s was not removed", name)
+		}
+	}
+	if _, ok := c.NextRun(manual); !ok {
+		t.Error("job added outside the config was removed")
+	}
+	if n := c.Count(); n != 2 {
+		t.Errorf("%d jobs, want c and the manual job", n)
+	}
+}
+
+func TestLoadFromConfigErrorKeepsOldJob(t *testing.T) {
+	c, ran := newConfigCron(t)
+	good := []JobConfig{
+		{Name: "a", Spec: "0 0 9 * * *", Func: "report", Timeout: time.Minute},
+		{Name: "b", Spec: "0 0 10 * * *", Func: "cleanup"},
+	}
+	if err := c.LoadFromConfig(good); err != nil {
+		t.Fatal(err)
+	}
+	a, _ := c.GetJobByName("a")
+
+	err := c.LoadFromConfig([]JobConfig{
+		{Name: "a", Spec: "bogus", Func: "report"},
+		{Name: "b", Spec: "0 0 10 * * *", Func: "missing"},
+		{Spec: "0 0 11 * * *", Func: "report"},
+		{Name: "d", Spec: "0 0 12 * * *", Func: "report"},
+		{Name: "d", Spec: "0 0 13 * * *", Func: "report"},
+	})
+	var spec *SpecError
+	if !errors.As(err, &spec) {
+		t.Fatalf("err = %v, want the first error, a *SpecError", err)
+	}
+	// 出错的任务保持原来的调度和配置，不会被删除
+	got, ok := c.GetJobByName("a")
+	if _, opt, _ := c.loadOptions(a.ID); !ok || got.ID != a.ID || got.Spec != "0 0 9 * * *" || opt.Timeout != time.Minute {
+		t.Errorf("a after a failed update: %+v", got)
+	}
+	b, ok := c.GetJobByName("b")
+	if !ok {
+		t.Fatal("b removed after its function went missing")
+	}
+	c.Call(b.ID)
+	if f := receive(t, ran); f != "cleanup" {
+		t.Errorf("b ran %s", f)
+	}
+	// 有效的任务照常处理，重复的名字只取第一个
+	if d, ok := c.GetJobByName("d"); !ok || d.Spec != "0 0 12 * * *" {
+		t.Errorf("d = %+v", d)
+	}
+	if n := c.Count(); n != 3 {
+		t.Errorf("%d jobs, want a, b and d", n)
+	}
+
+	// 修正配置后恢复正常
+	if err := c.LoadFromConfig(good); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := c.GetJobByName("d"); ok || c.Count() != 2 {
+		t.Errorf("jobs after the fixed config: %+v", c.ListJobs())
+	}
+}
+
+// waitJob 等待名为 name 的任务满足 cond，Watch 在后台应用配置
+func waitJob(t *testing.T, c *Cron, name string, cond func(JobInfo, bool) bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		info, ok := c.GetJobByName(name)
+		if cond(info, ok) {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for job %q, last %+v", name, info)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestWatchChanSource(t *testing.T) {
+	c, _ := newConfigCron(t)
+	ch := make(chan []JobConfig)
+	done := make(chan error, 1)
+	go func() { done <- c.Watch(context.Background(), ChanSource(ch)) }()
+
+	ch <- []JobConfig{{Name: "a", Spec: "0 0 9 * * *", Func: "report"}}
+	waitJob(t, c, "a", func(_ JobInfo, ok bool) bool { return ok })
+	// 应用失败的配置不会结束 Watch
+	ch <- []JobConfig{{Name: "a", Spec: "bogus", Func: "report"}}
+	ch <- []JobConfig{{Name: "a", Spec: "0 0 10 * * *", Func: "report"}}
+	waitJob(t, c, "a", func(info JobInfo, _ bool) bool { return info.Spec == "0 0 10 * * *" })
+	ch <- nil
+	waitJob(t, c, "a", func(_ JobInfo, ok bool) bool { return !ok })
+
+	close(ch)
+	if err := receive(t, done); err != nil {
+		t.Errorf("Watch() = %v after the source closed", err)
+	}
+}
+
+func TestWatchStopsWithContext(t *testing.T) {
+	c, _ := newConfigCron(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- c.Watch(ctx, ChanSource(make(chan []JobConfig))) }()
+	cancel()
+	if err := receive(t, done); !errors.Is(err, context.Canceled) {
+		t.Errorf("Watch() = %v, want context.Canceled", err)
+	}
+}
+
+func TestFileSource(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "jobs.yaml")
+	write := func(data string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	next := func(src *FileSource) ([]JobConfig, error) {
+		ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+		defer cancel()
+		return src.Next(ctx)
+	}
+
+	write("- name: a\n  spec: 0 0 9 * * *\n  func: report\n  timeout: 30s\n")
+	src := NewFileSource(path, 5*time.Millisecond)
+	cfg, err := next(src)
+	if err != nil || len(cfg) != 1 || cfg[0].Name != "a" || cfg[0].Timeout != 30*time.Second {
+		t.Fatalf("first read: %+v, %v", cfg, err)
+	}
+	// 内容没有变化时一直等待
+	if _, err := next(src); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("unchanged file: %v", err)
+	}
+
+	write("- name: a\n  spec: 0 0 10 * * *\n  func: report\n")
+	if cfg, err := next(src); err != nil || len(cfg) != 1 || cfg[0].Spec != "0 0 10 * * *" {
+		t.Fatalf("changed file: %+v, %v", cfg, err)
+	}
+
+	// 同一个解析错误只返回一次
+	write("- name: [")
+	if _, err := next(src); err == nil || errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("corrupt file: %v", err)
+	}
+	if _, err := next(src); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("same error returned twice: %v", err)
+	}
+
+	write("[]")
+	if cfg, err := next(src); err != nil || cfg == nil || len(cfg) != 0 {
+		t.Fatalf("empty list: %#v, %v", cfg, err)
+	}
+}
+
+func TestFileSourceJSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "jobs.json")
+	if err := os.WriteFile(path, []byte(`[{"name":"a","spec":"@hourly","func":"report","run_mode":2}]`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := NewFileSource(path, time.Second).Next(context.Background())
+	if err != nil || len(cfg) != 1 || cfg[0].RunMode != ModeJobParallel {
+		t.Fatalf("%+v, %v", cfg, err)
+	}
+	if _, err := NewFileSource(filepath.Join(t.TempDir(), "missing.json"), time.Second).Next(context.Background()); err == nil {
+		t.Error("missing file returned no error")
+	}
+}
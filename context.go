@@ -0,0 +1,137 @@
+package cron
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// AddJobContext 添加接收 ctx 的任务
+// 每次执行的 ctx 派生自传给 Start 的 ctx，尚未 Start 时派生自 context.Background()；
+// 任务被删除、暂停、调度器 Stop 或执行超过 WithTimeout 时 ctx 会被取消，任务应尽快返回
+// 返回的 id 与 AddJob 相同，失败返回 -1
+func (s *Cron) AddJobContext(spec string, f func(ctx context.Context), options ...Option) (id int) {
+	id, _ = s.AddJobContextE(spec, f, options...)
+	return id
+}
+
+// AddJobContextE 同 AddJobContext，但会返回失败原因
+func (s *Cron) AddJobContextE(spec string, f func(ctx context.Context), options ...Option) (id int, err error) {
+	s.warnStopped(spec)
+
+	return s.addSpec(spec, func(id int) jobFunc {
+		return func(parent context.Context) error {
+			ctx, done := s.jobContext(id, parent)
+			defer done()
+			f(ctx)
+			return nil
+		}
+	}, applyOptions(options...))
+}
+
+// AddJobContextE2 添加接收 ctx 并返回错误的任务，ctx 与 AddJobContext 相同，错误的处理与 AddJobE2 相同
+// 返回的 id 与 AddJob 相同，失败返回 -1
+func (s *Cron) AddJobContextE2(spec string, f func(ctx context.Context) error, options ...Option) (id int) {
+	s.warnStopped(spec)
+
+	id, _ = s.addSpec(spec, func(id int) jobFunc {
+		return func(parent context.Context) error {
+			ctx, done := s.jobContext(id, parent)
+			defer done()
+			return f(ctx)
+		}
+	}, applyOptions(options...))
+
+	return id
+}
+
+// jobContext 从 parent 为一次执行创建 ctx，返回的 done 需要在执行结束后调用
+// parent 派生自传给 Start 的 ctx，开启 WithTracerProvider 时携带本次执行的 span
+func (s *Cron) jobContext(id int, parent context.Context) (context.Context, context.CancelFunc) {
+	s.lock.RLock()
+	e, ok := s.load(id)
+	var timeout time.Duration
+	if ok {
+		timeout = e.opt.Timeout
+	}
+	s.lock.RUnlock()
+
+	var ctx context.Context
+	var cancel context.CancelFunc
+	if timeout > 0 {
+		ctx, cancel = context.WithTimeout(parent, timeout)
+	} else {
+		ctx, cancel = context.WithCancel(parent)
+	}
+	if !ok {
+		cancel()
+		return ctx, cancel
+	}
+	key := e.runs.add(cancel)
+	return ctx, func() {
+		e.runs.remove(key)
+		cancel()
+	}
+}
+
+// setRoot 替换派生执行 ctx 的根，并取消旧根上所有进行中的执行
+func (s *Cron) setRoot(ctx context.Context) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.cancelRoot != nil {
+		s.cancelRoot()
+	}
+	s.root, s.cancelRoot = context.WithCancel(ctx)
+}
+
+// cancels 任务进行中的执行的取消函数
+type cancels struct {
+	mu     sync.Mutex
+	next   int
+	fs     map[int]context.CancelFunc
+	closed bool
+}
+
+func newCancels() *cancels {
+	return &cancels{fs: make(map[int]context.CancelFunc)}
+}
+
+// add 记录取消函数，已经 cancelAll 时立即取消
+func (c *cancels) add(f context.CancelFunc) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		f()
+		return -1
+	}
+	c.next++
+	c.fs[c.next] = f
+	return c.next
+}
+
+func (c *cancels) remove(key int) {
+	c.mu.Lock()
+	delete(c.fs, key)
+	c.mu.Unlock()
+}
+
+// cancelAll 取消所有进行中的执行，reopen 之前新加入的也会立即取消
+func (c *cancels) cancelAll() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closed = true
+	for key, f := range c.fs {
+		f()
+		delete(c.fs, key)
+	}
+}
+
+// reopen 任务恢复后新的执行不再被立即取消
+func (c *cancels) reopen() {
+	c.mu.Lock()
+	c.closed = false
+	c.mu.Unlock()
+}
@@ -0,0 +1,115 @@
+package cron
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// ctxJob 添加等待 ctx 结束的任务，started 在开始执行时收到 ctx
+func ctxJob(c *Cron, options ...Option) (id int, started <-chan context.Context, cancelled <-chan error) {
+	s, done := make(chan context.Context, 2), make(chan error, 2)
+	id = c.AddJobContext("0 0 9 * * *", func(ctx context.Context) {
+		s <- ctx
+		<-ctx.Done()
+		done <- ctx.Err()
+	}, options...)
+	return id, s, done
+}
+
+func TestJobContextCancelled(t *testing.T) {
+	tests := []struct {
+		name string
+		stop func(c *Cron, id int)
+	}{
+		{"remove", func(c *Cron, id int) { c.RemoveJob(id) }},
+		{"pause", func(c *Cron, id int) { c.PauseJob(id) }},
+		{"stop", func(c *Cron, _ int) { c.Stop() }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewCron(WithLogger(DiscardLogger))
+			c.Start()
+			defer c.Stop()
+			id, started, cancelled := ctxJob(c)
+			c.CallAsync(id)
+			ctx := receive(t, started)
+			never(t, cancelled)
+
+			tt.stop(c, id)
+			if err := receive(t, cancelled); !errors.Is(err, context.Canceled) || ctx.Err() == nil {
+				t.Errorf("ctx ended with %v", err)
+			}
+		})
+	}
+}
+
+func TestJobContextWhilePaused(t *testing.T) {
+	c := NewCron(WithLogger(DiscardLogger))
+	c.Start()
+	defer c.Stop()
+	id, started, cancelled := ctxJob(c)
+	c.PauseJob(id)
+
+	// 暂停期间手动执行收到的 ctx 已经被取消
+	c.Call(id)
+	if ctx := receive(t, started); ctx.Err() == nil {
+		t.Error("ctx of a paused job is not cancelled")
+	}
+	receive(t, cancelled)
+
+	// 恢复后的执行不再被取消
+	c.ResumeJob(id)
+	c.CallAsync(id)
+	ctx := receive(t, started)
+	never(t, cancelled)
+	if ctx.Err() != nil {
+		t.Errorf("ctx after resume: %v", ctx.Err())
+	}
+	c.RemoveJob(id)
+	receive(t, cancelled)
+}
+
+type ctxValueKey struct{}
+
+func TestJobContextDerivesFromStartContext(t *testing.T) {
+	c := NewCron(WithLogger(DiscardLogger))
+	parent, cancel := context.WithCancel(context.WithValue(context.Background(), ctxValueKey{}, "parent"))
+	returned := make(chan struct{})
+	go func() {
+		c.StartContext(parent)
+		close(returned)
+	}()
+	for !c.IsRunning() {
+		time.Sleep(time.Millisecond)
+	}
+	id, started, cancelled := ctxJob(c)
+	c.CallAsync(id)
+	if ctx := receive(t, started); ctx.Value(ctxValueKey{}) != "parent" {
+		t.Error("job ctx does not derive from the StartContext ctx")
+	}
+
+	// 传入的 ctx 结束时取消执行，并等待执行结束后返回
+	cancel()
+	receive(t, cancelled)
+	receive(t, returned)
+	if c.IsRunning() {
+		t.Error("scheduler still running after StartContext returned")
+	}
+}
+
+func TestJobContextError(t *testing.T) {
+	c := NewCron(WithLogger(DiscardLogger))
+	boom := errors.New("boom")
+	id := c.AddJobContextE2("0 0 9 * * *", func(ctx context.Context) error {
+		if ctx.Err() != nil {
+			t.Error("ctx cancelled before the run ended")
+		}
+		return boom
+	})
+	c.Call(id)
+	if st, _ := c.Stats(id); st.Failures != 1 || !errors.Is(st.LastError, boom) {
+		t.Errorf("stats = %+v", st)
+	}
+}
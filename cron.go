@@ -2,30 +2,174 @@ package cron
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"math/rand"
+	"sort"
+	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/robfig/cron/v3"
+	"golang.org/x/time/rate"
+)
+
+var (
+	// ErrStopped 调度器已经停止
+	ErrStopped = errors.New("cron: scheduler stopped")
+	// ErrNotFound 任务不存在
+	ErrNotFound = errors.New("cron: job not found")
+	// ErrStopTimeout 停止调度时等待正在执行的任务超时
+	ErrStopTimeout = errors.New("cron: stop timed out")
+	// ErrBacklogFull 手动触发的积压数量达到上限，见 WithManualBacklog
+	ErrBacklogFull = errors.New("cron: manual trigger backlog full")
+	// ErrRandomWindow WithRandomWindow 的窗口超出了辅助方法的周期
+	ErrRandomWindow = errors.New("cron: random window out of range")
+	// ErrSecondsDisabled 使用 WithoutSeconds 时不能添加秒级任务
+	ErrSecondsDisabled = errors.New("cron: seconds are disabled by WithoutSeconds")
+	// ErrInvalidInterval 间隔或延迟必须大于 0
+	ErrInvalidInterval = errors.New("cron: interval must be positive")
+	// ErrNoSpec 没有提供任何 spec
+	ErrNoSpec = errors.New("cron: no spec")
+	// ErrTimeout 执行超过了 WithTimeout 设置的时长
+	ErrTimeout = errors.New("cron: job timed out")
 )
 
 type entry struct {
-	id     cron.EntryID
+	// counters 需要原子操作，放在首位保证 64 位对齐
+	counters counters
+	// id 任务 ID
+	id int
+	// ids 与 scheds 一一对应，分组任务会有多个
+	ids    []cron.EntryID
+	scheds []cron.Schedule
+	specs  []string
 	status uint
-	f      func()
+	// active 通过执行闸门的次数，所有内置执行策略共用，ModeJobQueue 下包括排队中的，见 gate.go
+	active int
+	// turn ModeJobQueue 下同一时刻只有持有它的执行能运行
+	turn   chan struct{}
+	paused bool
+	f      func(trigger)
+	opt    options
+	// addedAt 注册时间
+	addedAt time.Time
+	// history 最近的执行记录
+	history *history
+	// seen 最近执行过的幂等键
+	seen *idempotency
+	// raw 未经包装的任务函数，重新加载配置时使用
+	raw jobFunc
+	// runs 进行中的执行的 ctx，删除任务时取消，见 AddJobContext
+	runs *cancels
+	// result 最近一次执行的结果
+	result *result
+	// starts 进行中的执行的开始时间，见 WithStallDetection
+	starts *starts
 }
 
 type Cron struct {
 	c      *cron.Cron
+	parser cron.ScheduleParser
+	// custom 使用了 WithParser，不再按字段数检查 spec
+	custom bool
 	entry  sync.Map
 	lock   sync.RWMutex
 	idLock sync.Mutex
 	nextID int
+	state  int32
+	// loadCache EstimatedLoad 的缓存
+	loadCache loadCache
+	// dispatcher 开启 SingleDispatcher 时所有执行都经过它
+	dispatcher *dispatcher
+	// mutexes 互斥组的锁，见 WithMutexGroup
+	mutexLock sync.Mutex
+	mutexes   map[string]chan struct{}
+	// clock 调度使用的时钟，driver 设置 WithClock 时代替 robfig 的调度循环，否则为 nil
+	clock  Clock
+	driver *driver
+	// inline WithAsync(false) 的任务的定时触发经过它，第一次使用时创建
+	inline     *dispatcher
+	inlineOnce sync.Once
+	store      Store
+	// running 进行中的执行，Stop 时等待它们结束
+	running running
+	// root 执行 ctx 的根，派生自 Start 的 ctx，Stop 时取消
+	root       context.Context
+	cancelRoot context.CancelFunc
+	// names 任务名到 id 的索引，受 lock 保护
+	names map[string]int
+	// location 调度器使用的时区
+	location *time.Location
+	// hooks 默认的执行前后回调，受 lock 保护
+	hooks hooks
+	// seconds spec 是否带秒字段
+	seconds bool
+	// rand 随机选择 spec 和 Jitter 使用的随机数生成器
+	rand *lockedRand
+	// logger 任务没有单独设置日志时使用
+	logger Logger
+	// middlewares 和 lifecycle 作用于所有任务，见 WithMiddleware 和 WithHooks
+	middlewares []JobMiddleware
+	lifecycle   []Hooks
+	// metrics 所有任务的指标，见 WithMetrics
+	metrics []Metrics
+	// audits 所有任务的审计记录，见 WithAuditWriter
+	audits []AuditWriter
+	// snapshotWriter 和 snapshotInterval 见 WithSnapshotWriter，snapshotWriter 为 nil 时不写入
+	snapshotWriter   io.Writer
+	snapshotInterval time.Duration
+	// stallThreshold 和 stallHandler 见 WithStallDetection，stallThreshold 为 0 时不检查
+	stallThreshold time.Duration
+	stallHandler   func(id int, runningFor time.Duration)
+	// panicHandler 任务没有单独设置 PanicHandler 时使用
+	panicHandler func(id int, recovered interface{}, stack []byte)
+	// tracer 为每次执行创建 span，为 nil 时不创建，见 WithTracerProvider
+	tracer Tracer
+	// slots 全局并发名额，为 nil 时不限制，见 WithMaxConcurrency
+	slots chan struct{}
+	// jobs 持久化 AddStoredJob 添加的任务，funcs 和 typedFuncs 为 RegisterFunc 和 RegisterTypedFunc 注册的函数，受 lock 保护
+	jobs        JobStore
+	funcs       map[string]func()
+	typedFuncs  map[string]typedFunc
+	restoreOnce sync.Once
+	// configured 由 LoadFromConfig 管理的任务
+	configured configured
 }
 
+// 调度器运行状态，原子读写 Cron.state
+const (
+	stateIdle int32 = iota
+	stateRunning
+	stateStopped
+)
+
 const (
 	StatusReady = iota
 	StatusRunning
+	// StatusPaused 任务已暂停
+	StatusPaused
+)
+
+// 任务被跳过的原因，见 WithOnSkip
+const (
+	// SkipReasonSerial ModeJobSerial 下上一次执行还未结束
+	SkipReasonSerial = "serial"
+	// SkipReasonIdempotency 相同的幂等键已经执行过
+	SkipReasonIdempotency = "idempotency"
+	// SkipReasonLock 分布式锁被其他实例持有，见 WithDistributedLock
+	SkipReasonLock = "lock"
+	// SkipReasonConcurrency ModeJobParallel 下并发数已达上限
+	SkipReasonConcurrency = "concurrency"
+	// SkipReasonQueueFull ModeJobQueue 下排队的执行已达上限
+	SkipReasonQueueFull = "queue"
+	// SkipReasonRateLimit 执行频率超过了 WithRateLimit 的限制
+	SkipReasonRateLimit = "rate"
+	// SkipReasonMutex 互斥组内有其他任务正在执行，见 WithMutexGroup
+	SkipReasonMutex = "mutex"
 )
 
 type RunMode uint
@@ -38,6 +182,12 @@ const (
 	ModeJobSerial RunMode = iota
 	// ModeTimeFirst 优先满足定时性
 	ModeTimeFirst
+	// ModeJobParallel 允许有限的并发，同时最多执行 MaxConcurrency 次，超出的触发会被跳过
+	//   MaxConcurrency 为 1 时等同于 ModeJobSerial，见 WithMaxConcurrency
+	ModeJobParallel
+	// ModeJobQueue 任务串行，上一次执行未结束时本次触发排队，等上一次结束后紧接着执行
+	//   排队的数量由 QueueDepth 限制，超出的触发会被跳过，见 WithQueueDepth
+	ModeJobQueue
 )
 
 type options struct {
@@ -45,6 +195,8 @@ type options struct {
 	//   默认 ModeJobSerial
 	RunMode RunMode
 	// Immediately 是否立即执行，立即执行指的是在添加任务时就执行一次
+	//   立即执行的 panic 总会被捕获，不受 Recover 影响
+	//   立即执行与定时触发经过同一个执行策略，见 WithImmediately
 	//   默认 false
 	Immediately bool
 	// Random 随机模式
@@ -55,6 +207,123 @@ type options struct {
 	Random bool // 默认 false
 	// Recover 如果为true则捕获panic
 	Recover bool // 默认 true
+	// DriftCorrection 固定延迟任务的漂移修正，见 AddFixedDelayJob
+	//   默认 false
+	DriftCorrection bool
+	// Payload 任务携带的参数，PayloadJSON 为其 JSON 形式，只有 AddStoredTypedJob 会设置，见 AddTypedJob
+	//   默认 nil
+	Payload     interface{}
+	PayloadJSON json.RawMessage
+	// MutexGroup 和 MutexPolicy 见 WithMutexGroup
+	//   默认为空，不加入互斥组
+	MutexGroup  string
+	MutexPolicy MutexPolicy
+	// Async 定时触发是否各自在新的 goroutine 中执行，见 WithAsync
+	//   默认 true
+	Async bool
+	// OnSkip 任务本次触发被跳过时调用
+	//   默认 nil
+	OnSkip func(id int, reason string)
+	// OnSkipAt 同 OnSkip，同时给出触发时间
+	//   默认 nil
+	OnSkipAt func(id int, scheduledAt time.Time, reason string)
+	// Strategy 执行策略，设置后忽略 RunMode
+	//   默认 nil，按 RunMode 选择内置策略
+	Strategy RunStrategy
+	// HardTimeout 单次执行超过该时长视为被放弃，计入统计
+	//   默认 0，不限制
+	HardTimeout time.Duration
+	// ReleaseOnAbandon 执行被放弃时是否立即释放运行状态，
+	//   ModeJobSerial 下下一次触发可以继续执行
+	//   默认 false
+	ReleaseOnAbandon bool
+	// RandomWindow 在锚点附近随机，优先于 Random，见 WithRandomWindow
+	//   默认 nil
+	RandomWindow *randomWindow
+	// HistorySize 保留最近多少次执行记录，0 表示不记录
+	//   默认 16
+	HistorySize int
+	// ManualBacklog 同时进行中的手动调用上限，0 表示不限制
+	//   默认 0
+	ManualBacklog int
+	// IdempotencyKey 根据触发时间计算幂等键，见 WithIdempotencyKey
+	//   默认 nil
+	IdempotencyKey func(scheduledTime time.Time) string
+	// IdempotencyTTL 幂等键的保留时间
+	//   默认 1 小时
+	IdempotencyTTL time.Duration
+	// PanicHandler 捕获到 panic 时调用，见 WithPanicHandler
+	//   默认 nil，使用 NewCron 设置的 PanicHandler，都没有设置时交给 Logger
+	PanicHandler func(id int, recovered interface{}, stack []byte)
+	// Timeout 单次执行超过该时长后释放运行状态，见 WithTimeout
+	//   默认 0，不限制
+	Timeout time.Duration
+	// TimeoutHandler 执行超时时调用
+	//   默认 nil，打印到标准输出
+	TimeoutHandler func(id int, timeout time.Duration)
+	// Name 任务名，见 WithName
+	//   默认 ""
+	Name string
+	// Group 任务所属的分组，见 WithGroup
+	//   默认 ""
+	Group string
+	// RateLimiter 限制执行频率，见 WithRateLimit
+	//   默认 nil，不限制
+	RateLimiter *rate.Limiter
+	// RetryMax 任务返回错误或 panic 时的最大重试次数，见 WithRetryPolicy
+	//   默认 0，不重试
+	RetryMax int
+	// RetryBackoff 每次重试前的等待时间
+	//   默认 nil，不等待
+	RetryBackoff BackoffStrategy
+	// ErrorHandler 任务最终失败时调用
+	//   默认 nil，交给 Logger
+	ErrorHandler func(id int, attempt int, err error)
+	// OnError 每次以错误结束的执行调用，见 WithOnError
+	//   默认 nil
+	OnError func(id int, err error)
+	// Locker 分布式锁，见 WithDistributedLock
+	//   默认 nil
+	Locker Locker
+	// LockTTL 分布式锁的过期时间
+	//   默认 1 分钟
+	LockTTL time.Duration
+	// BeforeRun 每次执行前调用，见 WithBeforeRun
+	//   默认 nil，使用 SetDefaultHooks 设置的回调
+	BeforeRun func(id int)
+	// AfterRun 每次执行后调用，见 WithAfterRun
+	//   默认 nil，使用 SetDefaultHooks 设置的回调
+	AfterRun func(id int, d time.Duration)
+	// MaxConcurrency ModeJobParallel 下同时执行的上限，小于 1 时按 1 处理
+	//   默认 1
+	MaxConcurrency int
+	// Jitter 每次执行前随机等待的上限，见 WithJitter
+	//   默认 0，不等待
+	Jitter time.Duration
+	// QueueDepth ModeJobQueue 下最多排队的执行数
+	//   默认 1
+	QueueDepth int
+	// Func AddStoredJob 使用的函数名，不为空时任务会同步到 JobStore
+	//   默认 ""
+	Func string
+	// Timezone 任务的 spec 使用的时区，见 WithTimezone
+	//   默认 nil，使用调度器的时区
+	Timezone *time.Location
+	// Logger 任务使用的日志，见 WithLogger
+	//   默认 nil，使用调度器的日志
+	Logger Logger
+	// Middlewares 任务的中间件，见 WithMiddleware
+	//   默认 nil
+	Middlewares []JobMiddleware
+	// Hooks 任务的生命周期回调，见 WithHooks
+	//   默认 nil
+	Hooks []Hooks
+	// Metrics 任务的指标，见 WithMetrics
+	//   默认 nil
+	Metrics []Metrics
+	// AuditWriters 任务的审计记录，见 WithAuditWriter
+	//   默认 nil
+	AuditWriters []AuditWriter
 }
 
 type Option interface {
@@ -77,6 +346,10 @@ func (i _Immediately) apply(opts *options) {
 	opts.Immediately = bool(i)
 }
 
+// WithImmediately 添加任务时立即执行一次，只有任务注册成功（spec 有效）时才会执行
+// 立即执行和定时触发一样经过执行策略：ModeJobSerial 下同一时刻最多只有一次执行，
+// 第一次定时触发到来时如果立即执行还没结束，这次触发会被跳过并计入 Skipped；
+// 如果立即执行已经结束，这次触发正常执行，因此两次执行可能紧挨着发生
 func WithImmediately(i bool) Option {
 	return _Immediately(i)
 }
@@ -87,6 +360,10 @@ func (r _Random) apply(opts *options) {
 	opts.Random = bool(r)
 }
 
+// WithRandom 添加任务时随机选择辅助方法生成的 spec 中更小的字段，只在添加时随机一次，
+// 比如 AddHourJob 随机选择分和秒，AddSecondJob 随机选择第一次执行的秒数，
+// AddIntervalJob 随机延后第一次执行；对 AddJob 传入的 spec 不生效
+// 随机结果可以通过 WithRandSource 固定，需要对每次执行都随机错开请使用 WithJitter
 func WithRandom(r bool) Option {
 	return _Random(r)
 }
@@ -101,12 +378,78 @@ func WithRecover(r bool) Option {
 	return _Recover(r)
 }
 
+type _Async bool
+
+func (a _Async) apply(opts *options) {
+	opts.Async = bool(a)
+}
+
+// WithAsync 为 false 时任务的定时触发不再各自起 goroutine，而是交给调度器内部的同一个 goroutine 依次执行，
+// 所有 WithAsync(false) 的任务共用它，一个慢任务会推迟其他同步任务的执行，但不影响异步任务
+// 同步执行同样经过执行策略、WithMaxConcurrency 的全局名额等全部包装，ModeJobSerial 下不会因为与自己重叠而被跳过，
+// 等待全局名额时会阻塞其他同步任务；Call、Immediately 和依赖触发不受影响
+// 开启 WithSingleDispatcher 时所有执行本来就是同步的，该配置不生效
+func WithAsync(a bool) Option {
+	return _Async(a)
+}
+
+type _ManualBacklog int
+
+func (n _ManualBacklog) apply(opts *options) {
+	opts.ManualBacklog = int(n)
+}
+
+// WithManualBacklog 限制同时进行中的手动调用 (Call/CallE) 数量，超出的调用会被拒绝
+// 防止短时间内大量手动触发堆积 goroutine
+func WithManualBacklog(n int) Option {
+	return _ManualBacklog(n)
+}
+
+type _OnSkip func(id int, reason string)
+
+func (f _OnSkip) apply(opts *options) {
+	opts.OnSkip = f
+}
+
+// WithOnSkip 设置任务被跳过时的回调，reason 为 SkipReason* 常量之一
+// 每次跳过都会计入 JobStats.Skipped
+func WithOnSkip(f func(id int, reason string)) Option {
+	return _OnSkip(f)
+}
+
+type _OnSkipAt func(id int, scheduledAt time.Time, reason string)
+
+func (f _OnSkipAt) apply(opts *options) {
+	opts.OnSkipAt = f
+}
+
+// WithOnSkipAt 同 WithOnSkip，同时给出被跳过的触发时间，可以与 WithOnSkip 同时使用
+// 分布式锁导致的跳过，scheduledAt 为尝试获取锁的时间
+func WithOnSkipAt(f func(id int, scheduledAt time.Time, reason string)) Option {
+	return _OnSkipAt(f)
+}
 
 var defaultOpt = options{
-	RunMode:     ModeJobSerial,
-	Immediately: false,
-	Random:      false,
-	Recover:     true,
+	RunMode:        ModeJobSerial,
+	Immediately:    false,
+	Random:         false,
+	Recover:        true,
+	Async:          true,
+	HistorySize:    16,
+	IdempotencyTTL: time.Hour,
+	LockTTL:        time.Minute,
+	MaxConcurrency: 1,
+	QueueDepth:     1,
+}
+
+// skip 触发 OnSkip 和 OnSkipAt 回调，每次跳过只调用一次
+func (opt options) skip(id int, reason string, at time.Time) {
+	if opt.OnSkip != nil {
+		opt.OnSkip(id, reason)
+	}
+	if opt.OnSkipAt != nil {
+		opt.OnSkipAt(id, at, reason)
+	}
 }
 
 func applyOptions(opts ...Option) options {
@@ -118,13 +461,168 @@ func applyOptions(opts ...Option) options {
 	return opt
 }
 
-func NewCron() *Cron {
-	return &Cron{
-		c:      cron.New(cron.WithSeconds()),
-		entry:  sync.Map{},
-		lock:   sync.RWMutex{},
-		idLock: sync.Mutex{},
+// secondParser 与 cron.WithSeconds() 使用的解析器一致
+var secondParser = cron.NewParser(
+	cron.Second | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor,
+)
+
+// cronOptions 调度器级别的配置，通过 NewCron 传入
+type cronOptions struct {
+	// SingleDispatcher 所有任务由同一个 goroutine 按 id 顺序执行
+	//   默认 false
+	SingleDispatcher bool
+	// Store 持久化任务运行状态，Stop 时保存最近执行时间
+	//   默认 nil，不持久化
+	Store Store
+	// Location 解析 spec 使用的时区
+	//   默认 time.Local
+	Location *time.Location
+	// WithoutSeconds 使用五段式 spec，见 WithoutSeconds
+	//   默认 false
+	WithoutSeconds bool
+	// RandSource 随机源，见 WithRandSource
+	//   默认 nil，以当前时间为种子
+	RandSource rand.Source
+	// Logger 调度器的日志，见 WithLogger
+	//   默认 DefaultLogger
+	Logger Logger
+	// Middlewares 所有任务的中间件，见 WithMiddleware
+	//   默认 nil
+	Middlewares []JobMiddleware
+	// Hooks 所有任务的生命周期回调，见 WithHooks
+	//   默认 nil
+	Hooks []Hooks
+	// Metrics 所有任务的指标，见 WithMetrics
+	//   默认 nil
+	Metrics []Metrics
+	// AuditWriters 所有任务的审计记录，见 WithAuditWriter
+	//   默认 nil
+	AuditWriters []AuditWriter
+	// MaxConcurrency 所有任务同时执行的上限，小于 1 表示不限制
+	//   默认 0
+	MaxConcurrency int
+	// JobStore 持久化任务，见 WithJobStore
+	//   默认 nil，不持久化
+	JobStore JobStore
+	// TracerProvider 为每次执行创建 span，见 WithTracerProvider
+	//   默认 nil，不创建
+	TracerProvider TracerProvider
+	// PanicHandler 任务没有单独设置 PanicHandler 时使用，见 WithPanicHandler
+	//   默认 nil，交给 Logger
+	PanicHandler func(id int, recovered interface{}, stack []byte)
+	// Clock 见 WithClock
+	//   默认 nil，使用系统时间
+	Clock Clock
+	// SnapshotWriter 和 SnapshotInterval 见 WithSnapshotWriter
+	//   默认 nil，不写入
+	SnapshotWriter   io.Writer
+	SnapshotInterval time.Duration
+	// StallThreshold 和 StallHandler 见 WithStallDetection
+	//   默认 0，不检查
+	StallThreshold time.Duration
+	StallHandler   func(id int, runningFor time.Duration)
+	// CronOptions 创建底层 robfig/cron 时追加的配置，见 WithCronOptions
+	//   默认 nil
+	CronOptions []cron.Option
+	// Parser 自定义的 spec 解析器，见 WithParser
+	//   默认 nil，按 WithoutSeconds 选择
+	Parser cron.ScheduleParser
+}
+
+type CronOption interface {
+	applyCron(*cronOptions)
+}
+
+// CommonOption 既可以传给 NewCron 作为所有任务的默认值，也可以在添加任务时单独设置
+type CommonOption interface {
+	Option
+	CronOption
+}
+
+var defaultCronOpt = cronOptions{
+	SingleDispatcher: false,
+	Location:         time.Local,
+	Logger:           DefaultLogger,
+}
+
+func applyCronOptions(opts ...CronOption) cronOptions {
+	opt := defaultCronOpt
+	for _, o := range opts {
+		o.applyCron(&opt)
+	}
+
+	return opt
+}
+
+func NewCron(options ...CronOption) *Cron {
+	opt := applyCronOptions(options...)
+	if opt.Location == nil {
+		opt.Location = time.Local
+	}
+	if opt.Logger == nil {
+		opt.Logger = DefaultLogger
+	}
+	var parser cron.ScheduleParser = secondParser
+	if opt.WithoutSeconds {
+		parser = minuteParser
+	}
+	if opt.Parser != nil {
+		parser = opt.Parser
+	}
+	s := &Cron{
+		c:              cron.New(append([]cron.Option{cron.WithParser(parser), cron.WithLocation(opt.Location)}, opt.CronOptions...)...),
+		parser:         parser,
+		custom:         opt.Parser != nil,
+		seconds:        !opt.WithoutSeconds,
+		entry:          sync.Map{},
+		lock:           sync.RWMutex{},
+		idLock:         sync.Mutex{},
+		store:          opt.Store,
+		names:          make(map[string]int),
+		location:       opt.Location,
+		rand:           newLockedRand(opt.RandSource),
+		logger:         opt.Logger,
+		middlewares:    opt.Middlewares,
+		lifecycle:      opt.Hooks,
+		metrics:        opt.Metrics,
+		audits:         opt.AuditWriters,
+		jobs:           opt.JobStore,
+		funcs:          make(map[string]func()),
+		typedFuncs:     make(map[string]typedFunc),
+		mutexes:        make(map[string]chan struct{}),
+		configured:     configured{jobs: make(map[string]JobConfig)},
+		panicHandler:   opt.PanicHandler,
+		stallThreshold: opt.StallThreshold,
+		stallHandler:   opt.StallHandler,
+		snapshotWriter: opt.SnapshotWriter,
+	}
+	s.setRoot(nil)
+
+	s.snapshotInterval = opt.SnapshotInterval
+	if s.snapshotInterval <= 0 {
+		s.snapshotInterval = time.Minute
 	}
+
+	s.clock = systemClock{}
+	if opt.Clock != nil {
+		s.clock = opt.Clock
+		s.driver = newDriver()
+	}
+
+	if opt.TracerProvider != nil {
+		s.tracer = opt.TracerProvider.Tracer(tracerName)
+	}
+
+	if opt.MaxConcurrency > 0 {
+		s.slots = make(chan struct{}, opt.MaxConcurrency)
+	}
+
+	if opt.SingleDispatcher {
+		s.dispatcher = newDispatcher()
+		go s.dispatcher.loop()
+	}
+
+	return s
 }
 
 func (s *Cron) GetStatus(id int) uint {
@@ -134,12 +632,25 @@ func (s *Cron) GetStatus(id int) uint {
 	if !ok {
 		return StatusReady
 	}
-	return entryI.(*entry).status
+	return entryI.(*entry).getStatus()
+}
+
+// getStatus 暂停优先于运行状态，调用方需持有读锁
+func (e *entry) getStatus() uint {
+	if e.paused {
+		return StatusPaused
+	}
+	return e.status
 }
 
 // SetStatus 设置当前的任务状态，
 // 不推荐手动调用，存在风险
+// 只接受 StatusReady 和 StatusRunning，其他值会被忽略，暂停请使用 PauseJob
 func (s *Cron) SetStatus(id int, status uint) {
+	if status != StatusReady && status != StatusRunning {
+		s.logger.Error(fmt.Errorf("invalid status %v", status), "SetStatus ignored", "id", id)
+		return
+	}
 	s.lock.Lock()
 	defer s.lock.Unlock()
 	entryI, ok := s.entry.Load(id)
@@ -158,181 +669,852 @@ func (s *Cron) genID() int {
 	return s.nextID
 }
 
-// Call 调用该方法会立马执行目标函数
-func (s *Cron) Call(id int) {
+// load 返回 id 对应的任务
+func (s *Cron) load(id int) (*entry, bool) {
 	entryI, ok := s.entry.Load(id)
 	if !ok {
+		return nil, false
+	}
+	return entryI.(*entry), true
+}
+
+// loadOptions 返回任务及其当前配置，配置可能被 ReloadJob 替换，需要在锁内读取
+func (s *Cron) loadOptions(id int) (*entry, options, bool) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	e, ok := s.load(id)
+	if !ok {
+		return nil, options{}, false
+	}
+	return e, e.opt, true
+}
+
+// Call 在当前 goroutine 中同步触发一次执行，与定时触发一样经过执行策略：
+// ModeJobSerial 下任务正在运行时本次调用会被跳过，ModeTimeFirst 下会阻塞到执行结束
+// 需要知道是否真正开始执行请使用 CallAsync
+func (s *Cron) Call(id int) {
+	_ = s.CallE(id)
+}
+
+// CallE 同 Call，但会返回失败原因
+// 设置了 WithManualBacklog 时，同时进行中的手动调用超过上限会被拒绝并返回 ErrBacklogFull
+// 调度器 Stop 之后不再接受手动调用，返回 ErrStopped
+func (s *Cron) CallE(id int) error {
+	return s.call(id, trigger{source: SourceManual, at: time.Now()})
+}
+
+// CallAsync 在新的 goroutine 中触发一次执行，返回是否真正开始执行
+// 任务不存在、调度器已停止、积压已满或被执行策略跳过（比如 ModeJobSerial 下任务正在运行）时返回 false
+// 开启 WithDistributedLock 时，返回 true 之后仍可能因为获取不到锁而跳过
+func (s *Cron) CallAsync(id int) bool {
+	started := make(chan bool, 1)
+	t := trigger{source: SourceManual, at: time.Now(), started: started}
+	go func() {
+		if err := s.call(id, t); err != nil {
+			t.signal(false)
+		}
+	}()
+	return <-started
+}
+
+// CallWait 在新的 goroutine 中触发一次执行并等待它结束，返回是否真正开始执行
+// 与 CallAsync 一样经过执行策略，被跳过时立即返回 false 和 nil；
+// 任务不存在、调度器已停止或积压已满时返回 false 和对应的错误
+// 执行结束前 ctx 结束时返回 true 和 ctx.Err()，执行会在后台继续；执行本身的错误见 Stats 和 History
+func (s *Cron) CallWait(ctx context.Context, id int) (ran bool, err error) {
+	started := make(chan bool, 1)
+	done := make(chan struct{})
+	errCh := make(chan error, 1)
+	t := trigger{source: SourceManual, at: time.Now(), started: started, done: done}
+	go func() {
+		if err := s.call(id, t); err != nil {
+			errCh <- err
+			t.signal(false)
+		}
+	}()
+
+	select {
+	case ok := <-started:
+		if !ok {
+			select {
+			case err = <-errCh:
+			default:
+			}
+			return false, err
+		}
+	case <-ctx.Done():
+		return false, ctx.Err()
+	}
+
+	select {
+	case <-done:
+		return true, nil
+	case <-ctx.Done():
+		return true, ctx.Err()
+	}
+}
+
+// call 手动触发一次执行
+func (s *Cron) call(id int, t trigger) error {
+	e, opt, ok := s.loadOptions(id)
+	if !ok {
+		return ErrNotFound
+	}
+	if atomic.LoadInt32(&s.state) == stateStopped {
+		return ErrStopped
+	}
+
+	n := atomic.AddInt64(&e.counters.manual, 1)
+	defer atomic.AddInt64(&e.counters.manual, -1)
+	if limit := opt.ManualBacklog; limit > 0 && n > int64(limit) {
+		atomic.AddUint64(&e.counters.rejected, 1)
+		return ErrBacklogFull
+	}
+
+	s.execute(id, t)
+	return nil
+}
+
+// 执行的触发来源，见 AuditRecord
+const (
+	// SourceSchedule 按 spec 定时触发
+	SourceSchedule = "schedule"
+	// SourceManual 通过 Call 等方法手动触发
+	SourceManual = "manual"
+	// SourceImmediate 添加任务时的立即执行，见 WithImmediately
+	SourceImmediate = "immediate"
+	// SourceDependency 上游任务成功后触发，见 AddDependentJob
+	SourceDependency = "dependency"
+	// SourceRetry 失败后的重试，见 WithRetryPolicy
+	SourceRetry = "retry"
+)
+
+// execFunc 包装链中的一次执行，t 为本次的触发，见 wrap
+type execFunc func(t trigger)
+
+// trigger 描述一次执行是如何被触发的
+type trigger struct {
+	// source 触发来源
+	source string
+	// at 触发时间
+	at time.Time
+	// started 不为 nil 时通知是否真正开始执行，见 CallAsync
+	started chan<- bool
+	// done 不为 nil 时在执行结束后关闭，见 CallWait
+	done chan<- struct{}
+}
+
+// signal 通知调用方本次触发是否开始执行，只有第一次通知有效
+func (t trigger) signal(started bool) {
+	if t.started == nil {
 		return
 	}
-	e, ok := entryI.(*entry)
+	select {
+	case t.started <- started:
+	default:
+	}
+}
+
+// execute 执行一次任务，定时触发、立即执行和 Call 都经过这里，
+// 保证执行策略、panic 捕获、统计等对所有执行路径一致
+func (s *Cron) execute(id int, t trigger) {
+	s.lock.RLock()
+	e, ok := s.load(id)
+	var f func(trigger)
 	if ok {
-		e.f()
+		f = e.f
+	}
+	s.lock.RUnlock()
+
+	if f == nil {
+		t.signal(false)
+		return
 	}
+	f(t)
 }
 
-// AddJob 添加(更新)任务
-// 返回的 ID 可用于操作该定时任务（删除，调用 ...）
+// AddJob 添加任务，每次调用都会分配新的 id；设置了 WithName 且已有同名任务时替换该任务，
+// 更新已有任务请使用 UpdateJob 或 ReloadJob
+// 返回的 ID 可用于操作该定时任务（删除，调用 ...），失败返回 -1
 func (s *Cron) AddJob(spec string, f func(), options ...Option) (id int) {
-	var (
-		entryId cron.EntryID
-		err     error
-		ff      func()
-		opt     = applyOptions(options...)
-	)
+	id, _ = s.AddJobE(spec, f, options...)
+	return id
+}
+
+// AddJobE 同 AddJob，但会返回失败原因，spec 解析失败时错误中包含该 spec
+// 调度器 Stop 之后添加的任务依然会注册，但要等到下一次 Start 才会触发
+func (s *Cron) AddJobE(spec string, f func(), options ...Option) (id int, err error) {
+	s.warnStopped(spec)
+
+	return s.addSpec(spec, func(int) jobFunc { return plain(f) }, applyOptions(options...))
+}
+
+// jobFunc 任务函数的内部形式，ctx 为本次执行的父 ctx，接收 ctx 的任务由它派生，见 jobContext
+type jobFunc func(ctx context.Context) error
+
+// plain 将没有返回值的任务函数转换为内部使用的形式
+func plain(f func()) jobFunc {
+	return func(context.Context) error {
+		f()
+		return nil
+	}
+}
+
+// addSpec 解析 spec 并注册任务，build 根据分配到的 id 构造任务函数
+// 解析成功后才分配 id，失败不会占用 id；已有同名任务时原地替换并返回它的 id
+func (s *Cron) addSpec(spec string, build func(id int) jobFunc, opt options) (id int, err error) {
+	spec = withTimezone(spec, opt.Timezone)
+	sched, err := s.parse(spec)
+	if err != nil {
+		return -1, err
+	}
+
+	// 查找同名任务、替换或注册在同一次加锁内完成，并发添加同名任务时后来的一方替换先到的一方
+	s.lock.Lock()
+	if id, ok := s.replaceNamed(spec, sched, build, opt); ok {
+		return id, nil
+	}
 	id = s.genID()
+	e := s.insert(id, []string{spec}, []cron.Schedule{sched}, build(id), opt)
+	s.added(e)
 
-	if opt.Recover {
-		var f1 = f
-		f = func() {
-			defer func() {
-				err := recover()
-				if err != nil {
-					fmt.Printf("Recover:Job(%v):Err(%v)\n", id, err)
-				}
-			}()
-			f1()
+	return id, nil
+}
+
+// parse 解析 spec，失败返回 *SpecError
+func (s *Cron) parse(spec string) (cron.Schedule, error) {
+	if err := s.checkFieldCount(spec); err != nil {
+		return nil, &SpecError{Spec: spec, Err: err}
+	}
+	sched, err := s.parser.Parse(spec)
+	if err != nil {
+		return nil, &SpecError{Spec: spec, Field: s.badField(spec), Err: err}
+	}
+	return sched, nil
+}
+
+// AddGroupedJob 将同一个函数按多个 spec 注册为一个任务
+// 所有 spec 共用一个 id 和运行状态，删除等操作会作用于全部 spec，
+// Call 只会执行一次，而不是每个 spec 各执行一次
+// 任意一个 spec 解析失败都不会注册，返回 -1
+func (s *Cron) AddGroupedJob(specs []string, f func(), options ...Option) (id int) {
+	id, _ = s.AddGroupedJobE(specs, f, options...)
+	return id
+}
+
+// AddGroupedJobE 同 AddGroupedJob，但会返回失败原因，specs 为空返回 ErrNoSpec
+func (s *Cron) AddGroupedJobE(specs []string, f func(), options ...Option) (id int, err error) {
+	if len(specs) == 0 {
+		return -1, ErrNoSpec
+	}
+	s.warnStopped(strings.Join(specs, ";"))
+
+	scheds := make([]cron.Schedule, 0, len(specs))
+	for _, spec := range specs {
+		sched, err := s.parse(spec)
+		if err != nil {
+			return -1, err
 		}
+		scheds = append(scheds, sched)
 	}
 
-	switch opt.RunMode {
-	case ModeJobSerial:
-		ff = func() {
-			if s.GetStatus(id) == StatusRunning {
-				return
+	id = s.genID()
+	if err = s.addEntry(id, specs, scheds, plain(f), applyOptions(options...)); err != nil {
+		return -1, err
+	}
+
+	return id, nil
+}
+
+// warnStopped 调度器已停止时记录警告，任务会在下一次 Start 后触发
+func (s *Cron) warnStopped(spec string) {
+	if atomic.LoadInt32(&s.state) == stateStopped {
+		s.logger.Error(ErrStopped, "job will run after next Start", "spec", spec)
+	}
+}
+
+// wrap 根据配置包装任务函数
+func (s *Cron) wrap(id int, job jobFunc, opt options) func(trigger) {
+	job = s.retry(id, job, opt)
+	if s.tracer != nil {
+		job = s.trace(id, job, opt)
+	}
+	f := s.record(id, job)
+
+	// 立即执行的 panic 总会被捕获，超时和 dispatcher 会在其他 goroutine 中执行任务，
+	// 只靠 immediately 中的 recover 捕获不到，因此在这一层一起处理
+	var f1 = f
+	f = func(t trigger) {
+		if opt.Recover || t.source == SourceImmediate {
+			defer s.recoverPanic(id, opt)
+		}
+		f1(t)
+	}
+
+	if opt.HardTimeout > 0 {
+		f = s.hardTimeout(id, f, opt)
+	}
+
+	if opt.Timeout > 0 {
+		f = s.timeout(id, f, opt)
+	}
+
+	if opt.Locker != nil {
+		f = s.distributed(id, f, opt)
+	}
+
+	if len(s.middlewares) > 0 || len(opt.Middlewares) > 0 {
+		f = s.middleware(f, opt)
+	}
+
+	if s.slots != nil {
+		f = s.limit(f)
+	}
+
+	if opt.MutexGroup != "" {
+		f = s.exclusive(id, f, opt)
+	}
+
+	if opt.Jitter > 0 {
+		f = s.jitter(f, opt.Jitter)
+	}
+
+	strategy := opt.Strategy
+	if strategy == nil {
+		strategy = s.builtinStrategy(opt)
+	}
+
+	return func(t trigger) {
+		if !s.admit(id, t) {
+			t.signal(false)
+			s.settle(id)
+			return
+		}
+		g := func() {
+			if s.claim(id, t) {
+				f(t)
 			}
-			s.SetStatus(id, StatusRunning)
-			f()
-			s.SetStatus(id, StatusReady)
 		}
-	default:
-		ff = f
+		if t.started != nil {
+			g = func() {
+				if !s.claim(id, t) {
+					return
+				}
+				t.signal(true)
+				if t.done != nil {
+					defer close(t.done)
+				}
+				f(t)
+			}
+		}
+		run := func() { strategy.Execute(id, g) }
+		if st, ok := strategy.(scheduledStrategy); ok {
+			run = func() { st.executeAt(id, t.at, g) }
+		}
+		if t.source == SourceSchedule && s.takeForce(id) {
+			run = g
+		}
+		run = s.withHooks(id, run, opt)
+		s.running.add()
+		lane := s.dispatcher
+		if lane == nil && !opt.Async && t.source == SourceSchedule {
+			lane = s.inlineLane()
+		}
+		if lane != nil {
+			lane.submit(id, func() {
+				defer s.running.done()
+				defer t.signal(false)
+				defer s.settle(id)
+				if t.source == SourceImmediate {
+					defer s.recoverPanic(id, opt)
+				}
+				run()
+			})
+			return
+		}
+		defer s.running.done()
+		defer t.signal(false)
+		defer s.settle(id)
+		run()
 	}
+}
 
-	_, ok := s.entry.Load(id)
-	if ok {
-		s.RemoveJob(id)
+// addEntry 将包装后的任务注册到调度器，任务名重复时返回 ErrDuplicateName
+func (s *Cron) addEntry(id int, specs []string, scheds []cron.Schedule, f jobFunc, opt options) error {
+	s.lock.Lock()
+	if err := s.checkName(opt.Name); err != nil {
+		s.lock.Unlock()
+		return err
 	}
+	s.added(s.insert(id, specs, scheds, f, opt))
+	return nil
+}
 
-	entryId, err = s.c.AddFunc(spec, ff)
-	if err != nil {
-		return -1
+// insert 创建任务并注册到调度器，调用方需持有写锁并已检查任务名
+func (s *Cron) insert(id int, specs []string, scheds []cron.Schedule, f jobFunc, opt options) *entry {
+	e := &entry{
+		id:      id,
+		opt:     opt,
+		specs:   specs,
+		scheds:  scheds,
+		status:  StatusReady,
+		f:       s.wrap(id, f, opt),
+		addedAt: time.Now(),
+		history: newHistory(opt.HistorySize),
+		seen:    newIdempotency(opt.IdempotencyKey),
+		raw:     f,
+		runs:    newCancels(),
+		result:  &result{},
+		turn:    make(chan struct{}, 1),
+		starts:  newStarts(),
 	}
+	if opt.Name != "" {
+		s.names[opt.Name] = id
+	}
+	e.schedule(s)
+	s.entry.Store(id, e)
+	return e
+}
 
-	s.entry.Store(id, &entry{
-		id:     entryId,
-		status: StatusReady,
-		f:      ff,
-	})
+// added 释放 insert 时持有的写锁，记录日志并按配置立即执行一次
+func (s *Cron) added(e *entry) {
+	id, immediately := e.id, e.opt.Immediately
+	l, kv := e.logger(s)
+	s.lock.Unlock()
+	l.Info("job added", kv...)
 
-	if opt.Immediately {
-		go ff()
+	// 调度器已停止时不立即执行，任务等到下一次 Start 后按 spec 触发
+	if immediately && atomic.LoadInt32(&s.state) != stateStopped {
+		go s.immediately(id)
 	}
+}
 
-	return id
+// immediately 执行添加任务时的立即执行
+// 无论是否开启 Recover 都会捕获 panic：这里是单独的 goroutine，
+// 未捕获的 panic 会让整个进程退出，而定时触发的 panic 至少会被记录
+func (s *Cron) immediately(id int) {
+	defer func() {
+		if err := recover(); err != nil {
+			_, opt, _ := s.loadOptions(id)
+			s.handlePanic(id, opt, err)
+		}
+	}()
+	s.execute(id, trigger{source: SourceImmediate, at: time.Now()})
 }
 
-// AddSecondJob 添加秒级任务 0-59
+// addPeriod 注册辅助方法生成的六段式 spec，full 为空表示随机窗口无效
+func (s *Cron) addPeriod(full string, f func(), options []Option) (id int, spec string, err error) {
+	if full == "" {
+		return -1, "", ErrRandomWindow
+	}
+	spec = s.adapt(full)
+	id, err = s.AddJobE(spec, f, options...)
+	return id, spec, err
+}
+
+// addSecond 注册秒级任务
+func (s *Cron) addSecond(sec int, f func(), options []Option) (id int, spec string, err error) {
+	if !s.seconds {
+		return -1, "", ErrSecondsDisabled
+	}
+	return s.addPeriod(secondSpec(sec, applyOptions(options...), s.rand), f, options)
+}
+
+// AddSecondJob 添加秒级任务 1-59，每隔 sec 秒执行一次
+// 开启 WithRandom 时随机选择每分钟内第一次执行的秒数，之后仍然每隔 sec 秒执行
 func (s *Cron) AddSecondJob(sec int, f func(), options ...Option) (id int) {
-	if sec < 0 || sec > 59 {
+	id, _ = s.AddSecondJobE(sec, f, options...)
+	return id
+}
+
+// AddSecondJobE 同 AddSecondJob，但会返回失败原因
+func (s *Cron) AddSecondJobE(sec int, f func(), options ...Option) (id int, err error) {
+	id, _, err = s.addSecond(sec, f, options)
+	return id, err
+}
+
+// AddSecondJobWithSpec 同 AddSecondJob，同时返回生成的 spec，便于记录随机选择的结果
+func (s *Cron) AddSecondJobWithSpec(sec int, f func(), options ...Option) (id int, spec string) {
+	id, spec, _ = s.addSecond(sec, f, options)
+	return id, spec
+}
+
+// secondSpec 生成 AddSecondJob 使用的 spec
+func secondSpec(sec int, opt options, r *lockedRand) string {
+	if sec < 1 || sec > 59 {
 		sec = 59
 	}
 
-	spec := fmt.Sprintf("*/%d * * * * *", sec)
+	if opt.Random {
+		return fmt.Sprintf("%d/%d * * * * *", r.Intn(sec), sec)
+	}
 
-	return s.AddJob(spec, f, options...)
+	return fmt.Sprintf("*/%d * * * * *", sec)
 }
 
-// AddMinuteJob 添加分钟任务 0-59
+// AddMinuteJob 添加分钟任务 1-59，每隔 min 分钟执行一次
 func (s *Cron) AddMinuteJob(min int, f func(), options ...Option) (id int) {
-	if min < 0 || min > 59 {
+	id, _ = s.AddMinuteJobE(min, f, options...)
+	return id
+}
+
+// AddMinuteJobE 同 AddMinuteJob，但会返回失败原因
+func (s *Cron) AddMinuteJobE(min int, f func(), options ...Option) (id int, err error) {
+	id, _, err = s.addPeriod(minuteSpec(min, applyOptions(options...), s.rand), f, options)
+	return id, err
+}
+
+// AddMinuteJobWithSpec 同 AddMinuteJob，同时返回生成的 spec，便于记录随机选择的结果
+func (s *Cron) AddMinuteJobWithSpec(min int, f func(), options ...Option) (id int, spec string) {
+	id, spec, _ = s.addPeriod(minuteSpec(min, applyOptions(options...), s.rand), f, options)
+	return id, spec
+}
+
+// minuteSpec 生成 AddMinuteJob 使用的 spec，随机窗口无效时返回空字符串
+func minuteSpec(min int, opt options, r *lockedRand) string {
+	if min < 1 || min > 59 {
 		min = 59
 	}
 
-	opt := applyOptions(options...)
 	spec := fmt.Sprintf("0 */%d * * * *", min)
 
 	if opt.Random {
-		spec = fmt.Sprintf("%d */%d * * * *", rand.Intn(60), min)
+		spec = fmt.Sprintf("%d */%d * * * *", r.Intn(60), min)
 	}
 
-	return s.AddJob(spec, f, options...)
+	if opt.RandomWindow != nil {
+		off, ok := opt.RandomWindow.offset(time.Minute, r)
+		if !ok {
+			return ""
+		}
+		_, _, _, sec := splitOffset(off)
+		spec = fmt.Sprintf("%d */%d * * * *", sec, min)
+	}
+
+	return spec
 }
 
-// AddHourJob 添加小时任务 0-23
+// AddHourJob 添加小时任务 1-23，每隔 hour 小时执行一次
 func (s *Cron) AddHourJob(hour int, f func(), options ...Option) (id int) {
-	if hour < 0 || hour > 23 {
+	id, _ = s.AddHourJobE(hour, f, options...)
+	return id
+}
+
+// AddHourJobE 同 AddHourJob，但会返回失败原因
+func (s *Cron) AddHourJobE(hour int, f func(), options ...Option) (id int, err error) {
+	id, _, err = s.addPeriod(hourSpec(hour, applyOptions(options...), s.rand), f, options)
+	return id, err
+}
+
+// AddHourJobWithSpec 同 AddHourJob，同时返回生成的 spec，便于记录随机选择的结果
+func (s *Cron) AddHourJobWithSpec(hour int, f func(), options ...Option) (id int, spec string) {
+	id, spec, _ = s.addPeriod(hourSpec(hour, applyOptions(options...), s.rand), f, options)
+	return id, spec
+}
+
+// hourSpec 生成 AddHourJob 使用的 spec，随机窗口无效时返回空字符串
+func hourSpec(hour int, opt options, r *lockedRand) string {
+	if hour < 1 || hour > 23 {
 		hour = 23
 	}
 
-	opt := applyOptions(options...)
 	spec := fmt.Sprintf("0 0 */%d * * *", hour)
 
 	if opt.Random {
-		spec = fmt.Sprintf("%d %d */%d * * *", rand.Intn(60), rand.Intn(60), hour)
+		spec = fmt.Sprintf("%d %d */%d * * *", r.Intn(60), r.Intn(60), hour)
+	}
+
+	if opt.RandomWindow != nil {
+		off, ok := opt.RandomWindow.offset(time.Hour, r)
+		if !ok {
+			return ""
+		}
+		_, _, m, sec := splitOffset(off)
+		spec = fmt.Sprintf("%d %d */%d * * *", sec, m, hour)
 	}
 
-	return s.AddJob(spec, f, options...)
+	return spec
 }
 
-// AddDayJob 添加天任务 1-31
+// AddDayJob 添加天任务 1-31，每隔 day 天执行一次
 func (s *Cron) AddDayJob(day int, f func(), options ...Option) (id int) {
+	id, _ = s.AddDayJobE(day, f, options...)
+	return id
+}
+
+// AddDayJobE 同 AddDayJob，但会返回失败原因
+func (s *Cron) AddDayJobE(day int, f func(), options ...Option) (id int, err error) {
+	id, _, err = s.addPeriod(daySpec(day, applyOptions(options...), s.rand), f, options)
+	return id, err
+}
+
+// AddDayJobWithSpec 同 AddDayJob，同时返回生成的 spec，便于记录随机选择的结果
+func (s *Cron) AddDayJobWithSpec(day int, f func(), options ...Option) (id int, spec string) {
+	id, spec, _ = s.addPeriod(daySpec(day, applyOptions(options...), s.rand), f, options)
+	return id, spec
+}
+
+// daySpec 生成 AddDayJob 使用的 spec，随机窗口无效时返回空字符串
+func daySpec(day int, opt options, r *lockedRand) string {
 	if day < 1 || day > 31 {
 		day = 31
 	}
-	opt := applyOptions(options...)
 	spec := fmt.Sprintf("0 0 0 */%d * *", day)
 
 	if opt.Random {
-		spec = fmt.Sprintf("%d %d %d */%d * *", rand.Intn(60), rand.Intn(60), rand.Intn(24), day)
+		spec = fmt.Sprintf("%d %d %d */%d * *", r.Intn(60), r.Intn(60), r.Intn(24), day)
 	}
 
-	return s.AddJob(spec, f, options...)
+	if opt.RandomWindow != nil {
+		off, ok := opt.RandomWindow.offset(24*time.Hour, r)
+		if !ok {
+			return ""
+		}
+		_, h, m, sec := splitOffset(off)
+		spec = fmt.Sprintf("%d %d %d */%d * *", sec, m, h, day)
+	}
+
+	return spec
 }
 
-// AddMonthJob 添加月任务 0-12
+// AddMonthJob 添加月任务 1-12，每隔 mon 个月在 1 号执行一次
 func (s *Cron) AddMonthJob(mon int, f func(), options ...Option) (id int) {
-	if mon < 0 || mon > 12 {
+	id, _ = s.AddMonthJobE(mon, f, options...)
+	return id
+}
+
+// AddMonthJobE 同 AddMonthJob，但会返回失败原因
+func (s *Cron) AddMonthJobE(mon int, f func(), options ...Option) (id int, err error) {
+	id, _, err = s.addPeriod(monthSpec(mon, applyOptions(options...), s.rand), f, options)
+	return id, err
+}
+
+// AddMonthJobWithSpec 同 AddMonthJob，同时返回生成的 spec，便于记录随机选择的结果
+func (s *Cron) AddMonthJobWithSpec(mon int, f func(), options ...Option) (id int, spec string) {
+	id, spec, _ = s.addPeriod(monthSpec(mon, applyOptions(options...), s.rand), f, options)
+	return id, spec
+}
+
+// monthSpec 生成 AddMonthJob 使用的 spec，随机窗口无效时返回空字符串
+func monthSpec(mon int, opt options, r *lockedRand) string {
+	if mon < 1 || mon > 12 {
 		mon = 12
 	}
-	opt := applyOptions(options...)
-	spec := fmt.Sprintf("0 0 0 0 */%d *", mon)
+	spec := fmt.Sprintf("0 0 0 1 */%d *", mon)
 
 	if opt.Random {
-		spec = fmt.Sprintf("%d %d %d %d */%d *", rand.Intn(60), rand.Intn(60), rand.Intn(24), rand.Intn(29)+1, mon)
+		spec = fmt.Sprintf("%d %d %d %d */%d *", r.Intn(60), r.Intn(60), r.Intn(24), r.Intn(28)+1, mon)
 	}
 
-	return s.AddJob(spec, f, options...)
+	if opt.RandomWindow != nil {
+		off, ok := opt.RandomWindow.offset(monthWindow, r)
+		if !ok {
+			return ""
+		}
+		d, h, m, sec := splitOffset(off)
+		spec = fmt.Sprintf("%d %d %d %d */%d *", sec, m, h, d+1, mon)
+	}
+
+	return spec
 }
 
-// AddWeekJob 添加星期任务 1-7
+// AddWeekJob 添加星期任务 1-7，每周星期 week 执行一次，1 为周一，7 为周日
 func (s *Cron) AddWeekJob(week int, f func(), options ...Option) (id int) {
+	id, _ = s.AddWeekJobE(week, f, options...)
+	return id
+}
+
+// AddWeekJobE 同 AddWeekJob，但会返回失败原因
+func (s *Cron) AddWeekJobE(week int, f func(), options ...Option) (id int, err error) {
+	id, _, err = s.addPeriod(weekSpec(week, applyOptions(options...), s.rand), f, options)
+	return id, err
+}
+
+// AddWeekJobWithSpec 同 AddWeekJob，同时返回生成的 spec，便于记录随机选择的结果
+func (s *Cron) AddWeekJobWithSpec(week int, f func(), options ...Option) (id int, spec string) {
+	id, spec, _ = s.addPeriod(weekSpec(week, applyOptions(options...), s.rand), f, options)
+	return id, spec
+}
+
+// weekSpec 生成 AddWeekJob 使用的 spec，随机窗口无效时返回空字符串
+func weekSpec(week int, opt options, r *lockedRand) string {
 	if week < 1 || week > 7 {
 		week = 7
 	}
-	opt := applyOptions(options...)
-	spec := fmt.Sprintf("0 0 0 0 0 */%d", week)
+	// day-of-week 字段中 0 为周日
+	week %= 7
+	spec := fmt.Sprintf("0 0 0 * * %d", week)
 
 	if opt.Random {
-		spec = fmt.Sprintf("%d %d %d 0 0 */%d", rand.Intn(60), rand.Intn(60), rand.Intn(24), week)
+		spec = fmt.Sprintf("%d %d %d * * %d", r.Intn(60), r.Intn(60), r.Intn(24), week)
 	}
 
-	return s.AddJob(spec, f, options...)
+	if opt.RandomWindow != nil {
+		off, ok := opt.RandomWindow.offset(24*time.Hour, r)
+		if !ok {
+			return ""
+		}
+		_, h, m, sec := splitOffset(off)
+		spec = fmt.Sprintf("%d %d %d * * %d", sec, m, h, week)
+	}
+
+	return spec
 }
 
-// RemoveJob 删除任务
+// RemoveJob 删除任务，通过 AddRelativeJob 参考该任务的任务也会一并删除
 func (s *Cron) RemoveJob(id int) {
+	s.lock.Lock()
 	eid, ok := s.entry.Load(id)
+	var relatives []*entry
+	var l Logger
+	var kv []interface{}
 	if ok {
-		s.c.Remove(eid.(*entry).id)
+		l, kv = eid.(*entry).logger(s)
+		eid.(*entry).unschedule(s.c)
+		eid.(*entry).runs.cancelAll()
 		s.entry.Delete(id)
+		if name := eid.(*entry).opt.Name; s.names[name] == id {
+			delete(s.names, name)
+		}
+		relatives = s.relatives(id)
+	}
+	s.lock.Unlock()
+
+	if l != nil {
+		l.Info("job removed", kv...)
+		if e := eid.(*entry); e.opt.Func != "" && s.jobs != nil {
+			s.unpersistJob(e.opt.Name)
+		}
+	}
+
+	for _, e := range relatives {
+		s.RemoveJob(e.id)
 	}
 }
 
-func (s *Cron) Start(ctx context.Context) {
-	s.c.Start()
+// Start 启动调度，不阻塞，已经在运行时不做任何操作
+// 与 robfig/cron 相同，之后通过 Stop 停止；需要阻塞到 Stop 时使用 Run
+func (s *Cron) Start() {
+	s.start(nil)
+}
+
+// Run 启动调度并阻塞，直到 Stop 被调用后返回，不等待正在执行的任务结束
+// 已经在运行时阻塞到 Stop
+func (s *Cron) Run() {
+	<-s.start(nil)
+}
+
+// StartContext 启动调度，ctx 不为空时阻塞，ctx 结束后自动调用 Stop 并等待正在执行的任务全部结束后返回
+// AddJobContext 任务的 ctx 派生自传入的 ctx
+//
+// Deprecated: 使用 Start、Run 和 Stop
+func (s *Cron) StartContext(ctx context.Context) {
+	s.start(ctx)
 
 	// 如果ctx为空，不阻塞
 	if ctx != nil {
 		<-ctx.Done()
+		<-s.Stop().Done()
 	}
 }
+
+// start 启动调度，返回的 channel 在 Stop 或下一次启动时关闭
+func (s *Cron) start(ctx context.Context) <-chan struct{} {
+	if ctx == nil && s.IsRunning() {
+		s.lock.RLock()
+		defer s.lock.RUnlock()
+		return s.root.Done()
+	}
+	s.restoreOnce.Do(func() { _ = s.Restore() })
+	s.loadLastRuns()
+	s.setRoot(ctx)
+	atomic.StoreInt32(&s.state, stateRunning)
+	s.rewind()
+
+	s.lock.RLock()
+	done := s.root.Done()
+	s.lock.RUnlock()
+	if s.driver != nil {
+		go s.drive(done)
+	} else {
+		s.c.Start()
+	}
+	if s.stallThreshold > 0 {
+		go s.watch(done)
+	}
+	if s.snapshotWriter != nil {
+		go s.writeSnapshots(done)
+	}
+	return done
+}
+
+// IsRunning 调度器是否在运行，Start 之后、Stop 之前返回 true
+func (s *Cron) IsRunning() bool {
+	return atomic.LoadInt32(&s.state) == stateRunning
+}
+
+// Stop 停止调度，不再有新的触发，返回的 ctx 会在正在执行的任务全部结束后关闭，
+// 包括定时触发、立即执行和 Call 发起的执行；AddJobContext 任务的 ctx 会被取消
+// 停止后添加的任务依然会注册，等到下一次 Start 才会触发
+func (s *Cron) Stop() context.Context {
+	atomic.StoreInt32(&s.state, stateStopped)
+	stopped := s.c.Stop()
+	s.lock.RLock()
+	s.cancelRoot()
+	s.lock.RUnlock()
+	s.persistOnStop()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-stopped.Done()
+		<-s.running.wait()
+		cancel()
+	}()
+	return ctx
+}
+
+// StopWithTimeout 停止调度并最多等待 d，超时返回仍未结束的任务 id 和 ErrStopTimeout
+func (s *Cron) StopWithTimeout(d time.Duration) (unfinished []int, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), d)
+	defer cancel()
+	return s.Shutdown(ctx)
+}
+
+// Shutdown 停止调度并阻塞到正在执行的任务全部结束，适合在进程退出前调用
+// ctx 先结束时不再等待，返回仍未结束的任务 id 和 ErrStopTimeout，这些执行会在后台继续
+func (s *Cron) Shutdown(ctx context.Context) (unfinished []int, err error) {
+	select {
+	case <-s.Stop().Done():
+		return nil, nil
+	case <-ctx.Done():
+	}
+	return s.inflight(), ErrStopTimeout
+}
+
+// inflight 返回正在执行的任务 id，按 id 排序
+func (s *Cron) inflight() []int {
+	var ids []int
+	s.entry.Range(func(key, value interface{}) bool {
+		if atomic.LoadInt64(&value.(*entry).counters.inflight) > 0 {
+			ids = append(ids, key.(int))
+		}
+		return true
+	})
+	sort.Ints(ids)
+	return ids
+}
+
+// RunUntil 启动调度并阻塞，直到 ctx 结束或到达 deadline，以先到者为准，
+// 随后停止调度，不再有新的执行，并等待正在执行的任务全部结束后返回
+// ctx 可以为 nil，此时只受 deadline 控制
+func (s *Cron) RunUntil(ctx context.Context, deadline time.Time) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, cancel := context.WithDeadline(ctx, deadline)
+	defer cancel()
+
+	s.Start()
+	<-ctx.Done()
+	<-s.Stop().Done()
+}
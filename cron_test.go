@@ -0,0 +1,527 @@
+package cron
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// testStart FakeClock 的起始时间，选在整点前，方便测试按秒、分触发的任务
+var testStart = time.Date(2024, 1, 1, 8, 59, 55, 0, time.UTC)
+
+// newFakeCron 创建使用 FakeClock 的调度器，测试结束时停止
+func newFakeCron(t *testing.T, options ...CronOption) (*Cron, *FakeClock) {
+	t.Helper()
+	clk := NewFakeClock(testStart)
+	c := NewCron(append([]CronOption{WithClock(clk), WithLocation(time.UTC), WithLogger(DiscardLogger)}, options...)...)
+	t.Cleanup(func() { <-c.Stop().Done() })
+	return c, clk
+}
+
+// receive 等待 ch 收到值，超时视为失败
+func receive[T any](t *testing.T, ch <-chan T) T {
+	t.Helper()
+	select {
+	case v := <-ch:
+		return v
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for job")
+		panic("unreachable")
+	}
+}
+
+// never 确认 ch 在一小段时间内没有收到值
+func never[T any](t *testing.T, ch <-chan T) {
+	t.Helper()
+	select {
+	case v := <-ch:
+		t.Fatalf("unexpected receive: %v", v)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+// blockUntil 同 FakeClock.BlockUntil，超时视为失败，避免调度器没有安排下一次触发时测试一直阻塞
+func blockUntil(t *testing.T, clk *FakeClock, n int) {
+	t.Helper()
+	done := make(chan struct{})
+	go func() {
+		clk.BlockUntil(n)
+		close(done)
+	}()
+	receive(t, done)
+}
+
+// waitIdle 等待任务没有正在进行的执行
+func waitIdle(t *testing.T, c *Cron, id int) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for c.GetStatus(id) == StatusRunning {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for job to finish")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestImmediatelyWithInvalidSpec(t *testing.T) {
+	c := NewCron(WithLogger(DiscardLogger))
+	c.Start()
+	defer c.Stop()
+
+	ran := make(chan struct{}, 2)
+	f := func() { ran <- struct{}{} }
+	if id := c.AddJob("not a spec", f, WithImmediately(true)); id != -1 {
+		t.Errorf("AddJob returned id %d, want -1", id)
+	}
+	if _, err := c.AddJobE("61 * * * * *", f, WithImmediately(true)); err == nil {
+		t.Error("AddJobE accepted an invalid spec")
+	}
+	never(t, ran)
+	if n := c.Count(); n != 0 {
+		t.Errorf("%d jobs registered", n)
+	}
+}
+
+func TestImmediatelyGoesThroughSerialGate(t *testing.T) {
+	c := NewCron(WithLogger(DiscardLogger))
+	c.Start()
+	defer c.Stop()
+
+	var skips skipRecorder
+	block := make(chan struct{})
+	ran := make(chan string, 2)
+	id := c.AddJobContext("0 0 9 * * *", func(ctx context.Context) {
+		source, _ := ctx.Value(sourceKey{}).(string)
+		ran <- source
+		<-block
+	}, WithImmediately(true), skips.option())
+
+	if src := receive(t, ran); src != SourceImmediate {
+		t.Fatalf("first run source %q", src)
+	}
+	// 立即执行还没结束，定时触发被跳过
+	c.execute(id, trigger{source: SourceSchedule, at: time.Now()})
+	never(t, ran)
+	if got := skips.get(); len(got) != 1 || got[0] != SkipReasonSerial {
+		t.Fatalf("skips = %v, want [serial]", got)
+	}
+	close(block)
+}
+
+func TestImmediatelyNotRunWhileStopped(t *testing.T) {
+	c := NewCron(WithLogger(DiscardLogger))
+	c.Start()
+	c.Stop()
+
+	ran := make(chan struct{}, 1)
+	if _, err := c.AddJobE("0 0 9 * * *", func() { ran <- struct{}{} }, WithImmediately(true)); err != nil {
+		t.Fatal(err)
+	}
+	never(t, ran)
+}
+
+// errorLogger 记录 Error 日志的条数
+type errorLogger struct {
+	errors int32
+}
+
+func (l *errorLogger) Info(string, ...interface{}) {}
+
+func (l *errorLogger) Error(error, string, ...interface{}) {
+	atomic.AddInt32(&l.errors, 1)
+}
+
+func TestSetStatusIgnoresBogusStatus(t *testing.T) {
+	logger := &errorLogger{}
+	c := NewCron(WithLogger(logger))
+	ran := make(chan struct{}, 1)
+	id := c.AddJob("0 0 9 * * *", func() { ran <- struct{}{} })
+
+	for _, status := range []uint{StatusPaused, 99} {
+		c.SetStatus(id, status)
+		if got := c.GetStatus(id); got != StatusReady {
+			t.Errorf("SetStatus(%d): status = %d, want StatusReady", status, got)
+		}
+	}
+	if n := atomic.LoadInt32(&logger.errors); n != 2 {
+		t.Errorf("%d errors logged, want 2", n)
+	}
+
+	// 被忽略的 SetStatus 不影响任务执行
+	c.Call(id)
+	receive(t, ran)
+	if next, _ := c.NextRun(id); next.IsZero() {
+		t.Error("job has no next run after a bogus SetStatus")
+	}
+
+	// 合法的状态仍然生效
+	c.SetStatus(id, StatusRunning)
+	if got := c.GetStatus(id); got != StatusRunning {
+		t.Errorf("status = %d, want StatusRunning", got)
+	}
+}
+
+func TestImmediatelyRecoversWithoutRecover(t *testing.T) {
+	c := NewCron(WithLogger(DiscardLogger))
+	c.Start()
+	defer c.Stop()
+
+	type caught struct {
+		id        int
+		recovered interface{}
+		stack     []byte
+	}
+	panics := make(chan caught, 2)
+	handler := WithPanicHandler(func(id int, recovered interface{}, stack []byte) {
+		panics <- caught{id, recovered, stack}
+	})
+	boom := func() { panic("boom") }
+
+	// 未捕获的 panic 会让测试进程直接退出，能走到断言说明进程存活
+	id := c.AddJob("0 0 9 * * *", boom, WithImmediately(true), WithRecover(false), handler)
+	got := receive(t, panics)
+	if got.id != id || got.recovered != "boom" {
+		t.Fatalf("caught %v from job %d, want boom from job %d", got.recovered, got.id, id)
+	}
+	if len(got.stack) == 0 {
+		t.Error("empty stack")
+	}
+
+	// 同名替换触发的立即执行同样会被捕获
+	named, err := c.AddNamedJob("boom", "0 0 9 * * *", func() {}, WithRecover(false))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.AddNamedJob("boom", "0 0 9 * * *", boom, WithImmediately(true), WithRecover(false), handler); err != nil {
+		t.Fatal(err)
+	}
+	if got := receive(t, panics); got.id != named {
+		t.Fatalf("caught panic from job %d, want %d", got.id, named)
+	}
+
+	// 调度器仍然可以正常执行其他任务
+	ran := make(chan struct{}, 1)
+	c.Call(c.AddJob("0 0 9 * * *", func() { ran <- struct{}{} }))
+	receive(t, ran)
+}
+
+func TestImmediatelyRecoversOnOtherGoroutines(t *testing.T) {
+	// 超时和 dispatcher 会在其他 goroutine 中执行任务，panic 同样会被捕获
+	tests := []struct {
+		name    string
+		cron    []CronOption
+		options []Option
+	}{
+		{"timeout", nil, []Option{WithTimeout(time.Second)}},
+		{"hard timeout", nil, []Option{WithHardTimeout(time.Second)}},
+		{"single dispatcher", []CronOption{WithSingleDispatcher(true)}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewCron(append([]CronOption{WithLogger(DiscardLogger)}, tt.cron...)...)
+			c.Start()
+			defer c.Stop()
+			panics := make(chan int, 1)
+			options := append([]Option{WithImmediately(true), WithRecover(false),
+				WithPanicHandler(func(id int, _ interface{}, _ []byte) { panics <- id })}, tt.options...)
+			id := c.AddJob("0 0 9 * * *", func() { panic("boom") }, options...)
+			if got := receive(t, panics); got != id {
+				t.Errorf("caught panic from job %d, want %d", got, id)
+			}
+			waitIdle(t, c, id)
+		})
+	}
+}
+
+// blockingJob 添加一个阻塞到 release 被调用的任务，started 在每次开始执行时收到值
+// 测试结束时自动 release，避免 Stop 一直等待
+func blockingJob(t *testing.T, c *Cron) (id int, started <-chan struct{}, release func()) {
+	t.Helper()
+	block := make(chan struct{})
+	ch := make(chan struct{}, 4)
+	var once sync.Once
+	release = func() { once.Do(func() { close(block) }) }
+	t.Cleanup(release)
+	id = c.AddJob("0 0 9 * * *", func() {
+		ch <- struct{}{}
+		<-block
+	})
+	return id, ch, release
+}
+
+func TestShutdownDrainsRunningJobs(t *testing.T) {
+	c := NewCron(WithLogger(DiscardLogger))
+	c.Start()
+	id, started, release := blockingJob(t, c)
+	c.CallAsync(id)
+	receive(t, started)
+
+	type result struct {
+		unfinished []int
+		err        error
+	}
+	done := make(chan result, 1)
+	go func() {
+		unfinished, err := c.Shutdown(context.Background())
+		done <- result{unfinished, err}
+	}()
+	never(t, done)
+	if c.IsRunning() {
+		t.Error("scheduler still running during Shutdown")
+	}
+	if err := c.CallE(id); err != ErrStopped {
+		t.Errorf("CallE during Shutdown = %v, want ErrStopped", err)
+	}
+
+	release()
+	if r := receive(t, done); r.err != nil || r.unfinished != nil {
+		t.Errorf("Shutdown() = %v, %v", r.unfinished, r.err)
+	}
+
+	// 再次停止立即返回
+	if unfinished, err := c.Shutdown(context.Background()); err != nil || unfinished != nil {
+		t.Errorf("second Shutdown() = %v, %v", unfinished, err)
+	}
+	receive(t, c.Stop().Done())
+}
+
+func TestShutdownTimeout(t *testing.T) {
+	c := NewCron(WithLogger(DiscardLogger))
+	c.Start()
+	id, started, release := blockingJob(t, c)
+	c.CallAsync(id)
+	receive(t, started)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	unfinished, err := c.Shutdown(ctx)
+	if err != ErrStopTimeout || len(unfinished) != 1 || unfinished[0] != id {
+		t.Fatalf("Shutdown() = %v, %v, want [%d] and ErrStopTimeout", unfinished, err, id)
+	}
+
+	// 超时之后执行在后台继续，结束后再次停止不再超时
+	release()
+	waitIdle(t, c, id)
+	if unfinished, err := c.Shutdown(context.Background()); err != nil || unfinished != nil {
+		t.Errorf("Shutdown() after the job finished = %v, %v", unfinished, err)
+	}
+}
+
+func TestRunUntilDeadline(t *testing.T) {
+	c := NewCron(WithLogger(DiscardLogger))
+	id, started, release := blockingJob(t, c)
+	deadline := time.Now().Add(50 * time.Millisecond)
+	done := make(chan struct{})
+	go func() {
+		c.RunUntil(nil, deadline)
+		close(done)
+	}()
+	for !c.IsRunning() {
+		time.Sleep(time.Millisecond)
+	}
+	c.CallAsync(id)
+	receive(t, started)
+
+	// 到达 deadline 之后停止调度，等待正在执行的任务结束
+	time.Sleep(time.Until(deadline))
+	never(t, done)
+	if c.IsRunning() {
+		t.Error("scheduler still running after the deadline")
+	}
+	release()
+	receive(t, done)
+}
+
+func TestRunUntilContext(t *testing.T) {
+	c := NewCron(WithLogger(DiscardLogger))
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		c.RunUntil(ctx, time.Now().Add(time.Hour))
+		close(done)
+	}()
+	for !c.IsRunning() {
+		time.Sleep(time.Millisecond)
+	}
+	never(t, done)
+	cancel()
+	receive(t, done)
+	if c.IsRunning() {
+		t.Error("scheduler still running after RunUntil returned")
+	}
+}
+
+func TestStopWithTimeout(t *testing.T) {
+	c := NewCron(WithLogger(DiscardLogger))
+	c.Start()
+	idle := c.AddJob("0 0 9 * * *", func() {})
+	a, startedA, releaseA := blockingJob(t, c)
+	b, startedB, releaseB := blockingJob(t, c)
+	c.Call(idle)
+	c.CallAsync(b)
+	c.CallAsync(a)
+	receive(t, startedA)
+	receive(t, startedB)
+
+	start := time.Now()
+	unfinished, err := c.StopWithTimeout(30 * time.Millisecond)
+	if err != ErrStopTimeout || len(unfinished) != 2 || unfinished[0] != a || unfinished[1] != b {
+		t.Fatalf("StopWithTimeout() = %v, %v, want [%d %d] and ErrStopTimeout", unfinished, err, a, b)
+	}
+	if d := time.Since(start); d < 30*time.Millisecond {
+		t.Errorf("returned after %v, want at least the timeout", d)
+	}
+
+	releaseA()
+	waitIdle(t, c, a)
+	if unfinished, err := c.StopWithTimeout(30 * time.Millisecond); err != ErrStopTimeout || len(unfinished) != 1 || unfinished[0] != b {
+		t.Errorf("second StopWithTimeout() = %v, %v, want [%d]", unfinished, err, b)
+	}
+	releaseB()
+	if unfinished, err := c.StopWithTimeout(time.Second); err != nil || unfinished != nil {
+		t.Errorf("StopWithTimeout() after all jobs finished = %v, %v", unfinished, err)
+	}
+}
+
+func TestCallWait(t *testing.T) {
+	c := NewCron(WithLogger(DiscardLogger))
+	c.Start()
+	var finished int32
+	id := c.AddJob("0 0 9 * * *", func() {
+		time.Sleep(20 * time.Millisecond)
+		atomic.StoreInt32(&finished, 1)
+	})
+	if ran, err := c.CallWait(context.Background(), id); !ran || err != nil {
+		t.Fatalf("CallWait() = %v, %v", ran, err)
+	}
+	if atomic.LoadInt32(&finished) != 1 {
+		t.Error("CallWait returned before the job finished")
+	}
+
+	if ran, err := c.CallWait(context.Background(), 42); ran || err != ErrNotFound {
+		t.Errorf("unknown job: %v, %v", ran, err)
+	}
+	<-c.Stop().Done()
+	if ran, err := c.CallWait(context.Background(), id); ran || err != ErrStopped {
+		t.Errorf("stopped scheduler: %v, %v", ran, err)
+	}
+}
+
+func TestCallWaitSkippedAndTimeout(t *testing.T) {
+	c := NewCron(WithLogger(DiscardLogger))
+	c.Start()
+	defer c.Stop()
+	id, started, release := blockingJob(t, c)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	// ctx 先结束时执行在后台继续
+	if ran, err := c.CallWait(ctx, id); !ran || err != context.DeadlineExceeded {
+		t.Fatalf("CallWait() = %v, %v, want true and DeadlineExceeded", ran, err)
+	}
+	receive(t, started)
+
+	// ModeJobSerial 下正在运行时被跳过
+	if ran, err := c.CallWait(context.Background(), id); ran || err != nil {
+		t.Errorf("skipped run: %v, %v", ran, err)
+	}
+	release()
+	waitIdle(t, c, id)
+}
+
+func TestCallWaitBacklogFull(t *testing.T) {
+	c := NewCron(WithLogger(DiscardLogger))
+	c.Start()
+	defer c.Stop()
+	block := make(chan struct{})
+	t.Cleanup(func() { close(block) })
+	started := make(chan struct{}, 1)
+	id := c.AddJob("0 0 9 * * *", func() {
+		started <- struct{}{}
+		<-block
+	}, WithManualBacklog(1))
+	c.CallAsync(id)
+	receive(t, started)
+	if ran, err := c.CallWait(context.Background(), id); ran || err != ErrBacklogFull {
+		t.Errorf("CallWait() = %v, %v, want ErrBacklogFull", ran, err)
+	}
+}
+
+func TestGroupedJobSharesOneID(t *testing.T) {
+	c, clk := newFakeCron(t)
+	ran := make(chan firing, 8)
+	id := c.AddGroupedJob([]string{"3 * * * * *", "1 * * * * *"}, recordAs(clk, ran, "grouped"))
+	if id < 0 {
+		t.Fatal("AddGroupedJob failed")
+	}
+	nine := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	if next, _ := c.NextRun(id); !next.Equal(nine.Add(time.Second)) {
+		t.Errorf("NextRun = %v, want the earliest spec", next)
+	}
+	if st, _ := c.Stats(id); len(st.Specs) != 2 || st.Specs[0] != "3 * * * * *" {
+		t.Errorf("specs = %v", st.Specs)
+	}
+	c.Start()
+
+	got := stepFires(t, clk, ran, 9)
+	if len(got) != 2 || !got[0].at.Equal(nine.Add(time.Second)) || !got[1].at.Equal(nine.Add(3*time.Second)) {
+		t.Fatalf("fires = %v, want 09:00:01 and 09:00:03", got)
+	}
+	// 所有 spec 共用统计，Call 只执行一次
+	c.Call(id)
+	receive(t, ran)
+	never(t, ran)
+	if st, _ := c.Stats(id); st.Runs != 3 {
+		t.Errorf("runs = %d, want 3", st.Runs)
+	}
+	if n := c.Count(); n != 1 {
+		t.Errorf("%d jobs, want 1", n)
+	}
+}
+
+func TestGroupedJobPauseAndRemove(t *testing.T) {
+	c, clk := newFakeCron(t)
+	ran := make(chan firing, 8)
+	id := c.AddGroupedJob([]string{"1 * * * * *", "3 * * * * *"}, recordAs(clk, ran, "grouped"))
+	c.Start()
+
+	// 暂停和删除作用于所有 spec
+	c.PauseJob(id)
+	clk.Advance(9 * time.Second)
+	never(t, ran)
+
+	// 恢复后两个 spec 都继续触发，现在是 09:00:04
+	c.ResumeJob(id)
+	blockUntil(t, clk, 1)
+	clk.Advance(57 * time.Second)
+	receive(t, ran)
+	blockUntil(t, clk, 1)
+	clk.Advance(2 * time.Second)
+	receive(t, ran)
+
+	c.RemoveJob(id)
+	clk.Advance(time.Minute)
+	never(t, ran)
+	if _, ok := c.NextRun(id); ok {
+		t.Error("removed job still scheduled")
+	}
+}
+
+func TestGroupedJobErrors(t *testing.T) {
+	c := NewCron(WithLogger(DiscardLogger))
+	if id, err := c.AddGroupedJobE(nil, func() {}); id != -1 || err != ErrNoSpec {
+		t.Errorf("no specs: id %d, err %v", id, err)
+	}
+	// 任意一个 spec 无效都不注册
+	id, err := c.AddGroupedJobE([]string{"0 0 9 * * *", "bogus"}, func() {})
+	var spec *SpecError
+	if id != -1 || !errors.As(err, &spec) || spec.Spec != "bogus" {
+		t.Errorf("invalid spec: id %d, err %v", id, err)
+	}
+	if n := c.Count(); n != 0 {
+		t.Errorf("%d jobs registered", n)
+	}
+}
@@ -0,0 +1,66 @@
+package cron
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+type _DriftCorrection bool
+
+func (d _DriftCorrection) apply(opts *options) {
+	opts.DriftCorrection = bool(d)
+}
+
+// WithDriftCorrection 固定延迟任务的漂移修正
+func WithDriftCorrection(d bool) Option {
+	return _DriftCorrection(d)
+}
+
+// AddFixedDelayJob 添加固定延迟任务
+// 与 @every 不同，下一次执行在本次执行结束 delay 之后才开始，
+// 执行耗时和调度延迟都会累积到周期里，长期运行后执行时间会越来越晚
+// 开启 WithDriftCorrection 后，下一次执行时间从上一次计划时间算起，
+// 扣除执行耗时，平均周期尽量接近 delay；如果执行耗时超过 delay 则结束后立即执行
+// 被互斥组、分布式锁、限流等跳过的触发同样会安排下一次执行
+func (s *Cron) AddFixedDelayJob(delay time.Duration, f func(), options ...Option) (id int) {
+	id, _ = s.AddFixedDelayJobE(delay, f, options...)
+	return id
+}
+
+// AddFixedDelayJobE 同 AddFixedDelayJob，但会返回失败原因，delay 不大于 0 返回 ErrInvalidInterval
+func (s *Cron) AddFixedDelayJobE(delay time.Duration, f func(), options ...Option) (id int, err error) {
+	if delay <= 0 {
+		return -1, ErrInvalidInterval
+	}
+	spec := fmt.Sprintf("@delay %v", delay)
+	s.warnStopped(spec)
+
+	opt := applyOptions(options...)
+	id = s.genID()
+
+	// 下一次执行在每次触发结束后安排，包括 panic 和被跳过的触发，见 settle
+	var then func(planned time.Time)
+	then = func(planned time.Time) {
+		s.reschedule(id, &onceSchedule{at: nextDelay(s.clock.Now(), planned, delay, opt.DriftCorrection), then: then})
+	}
+	err = s.addEntry(id, []string{spec}, []cron.Schedule{&onceSchedule{at: s.clock.Now().Add(delay), then: then}}, plain(f), opt)
+	if err != nil {
+		return -1, err
+	}
+
+	return id, nil
+}
+
+// nextDelay 计算固定延迟任务的下一次执行时间，now 为任务结束的时间
+func nextDelay(now, planned time.Time, delay time.Duration, driftCorrection bool) time.Time {
+	if !driftCorrection {
+		return now.Add(delay)
+	}
+	next := planned.Add(delay)
+	if next.Before(now) {
+		return now
+	}
+	return next
+}
@@ -0,0 +1,142 @@
+package cron
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestFixedDelayCadence(t *testing.T) {
+	c, clk := newFakeCron(t)
+	ran := make(chan time.Time, 1)
+	if _, err := c.AddFixedDelayJobE(time.Second, func() { ran <- clk.Now() }); err != nil {
+		t.Fatal(err)
+	}
+	c.Start()
+
+	for i := 1; i <= 5; i++ {
+		blockUntil(t, clk, 1)
+		clk.Advance(time.Second)
+		if at, want := receive(t, ran), testStart.Add(time.Duration(i)*time.Second); !at.Equal(want) {
+			t.Fatalf("run %d at %v, want %v", i, at, want)
+		}
+	}
+}
+
+func TestFixedDelayDoesNotWaitForNextPeriodEarly(t *testing.T) {
+	c, clk := newFakeCron(t)
+	ran := make(chan struct{}, 1)
+	c.AddFixedDelayJob(2*time.Second, func() { ran <- struct{}{} })
+	c.Start()
+
+	blockUntil(t, clk, 1)
+	clk.Advance(time.Second)
+	never(t, ran)
+	clk.Advance(time.Second)
+	receive(t, ran)
+}
+
+func TestFixedDelayKeepsFiringAfterPanic(t *testing.T) {
+	c, clk := newFakeCron(t)
+	ran := make(chan struct{}, 1)
+	c.AddFixedDelayJob(time.Second, func() {
+		ran <- struct{}{}
+		panic("boom")
+	})
+	c.Start()
+
+	for i := 0; i < 4; i++ {
+		blockUntil(t, clk, 1)
+		clk.Advance(time.Second)
+		receive(t, ran)
+	}
+}
+
+func TestFixedDelayKeepsFiringAfterSkip(t *testing.T) {
+	c, clk := newFakeCron(t)
+	var skips skipRecorder
+	hold, release := make(chan struct{}), make(chan struct{})
+	var once sync.Once
+	unblock := func() { once.Do(func() { close(release) }) }
+	// 失败时也要放行，否则 Stop 会一直等待
+	t.Cleanup(unblock)
+	busy := c.AddJob("0 0 0 1 1 *", func() {
+		close(hold)
+		<-release
+	}, WithMutexGroup("db", MutexSkip))
+	ran := make(chan time.Time, 1)
+	id := c.AddFixedDelayJob(time.Second, func() { ran <- clk.Now() }, WithMutexGroup("db", MutexSkip), skips.option())
+	c.Start()
+
+	// 互斥组被占用，这一次触发被跳过
+	go c.Call(busy)
+	receive(t, hold)
+	blockUntil(t, clk, 1)
+	clk.Advance(time.Second)
+	blockUntil(t, clk, 1)
+	never(t, ran)
+	if got := skips.get(); len(got) != 1 || got[0] != SkipReasonMutex {
+		t.Fatalf("skips = %v, want [mutex]", got)
+	}
+	if next, _ := c.NextRun(id); !next.Equal(testStart.Add(2 * time.Second)) {
+		t.Fatalf("NextRun after skip = %v, want %v", next, testStart.Add(2*time.Second))
+	}
+
+	unblock()
+	waitIdle(t, c, busy)
+	clk.Advance(time.Second)
+	if at := receive(t, ran); !at.Equal(testStart.Add(2 * time.Second)) {
+		t.Fatalf("ran at %v after the skip", at)
+	}
+}
+
+func TestFixedDelayKeepsFiringAfterLockMiss(t *testing.T) {
+	c, clk := newFakeCron(t)
+	locker := &flakyLocker{misses: 2}
+	ran := make(chan struct{}, 1)
+	c.AddFixedDelayJob(time.Second, func() { ran <- struct{}{} }, WithDistributedLock(locker))
+	c.Start()
+
+	for i := 0; i < 2; i++ {
+		blockUntil(t, clk, 1)
+		clk.Advance(time.Second)
+		never(t, ran)
+	}
+	blockUntil(t, clk, 1)
+	clk.Advance(time.Second)
+	receive(t, ran)
+}
+
+// flakyLocker 前 misses 次 TryLock 失败，之后总能拿到锁
+type flakyLocker struct {
+	mu     sync.Mutex
+	misses int
+}
+
+func (l *flakyLocker) TryLock(context.Context, string, time.Duration) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.misses > 0 {
+		l.misses--
+		return false, nil
+	}
+	return true, nil
+}
+
+func (l *flakyLocker) Unlock(context.Context, string) error { return nil }
+
+func TestNextDelay(t *testing.T) {
+	planned := testStart
+	now := planned.Add(300 * time.Millisecond)
+	if got := nextDelay(now, planned, time.Second, false); !got.Equal(now.Add(time.Second)) {
+		t.Errorf("without correction: %v", got)
+	}
+	if got := nextDelay(now, planned, time.Second, true); !got.Equal(planned.Add(time.Second)) {
+		t.Errorf("with correction: %v", got)
+	}
+	late := planned.Add(3 * time.Second)
+	if got := nextDelay(late, planned, time.Second, true); !got.Equal(late) {
+		t.Errorf("overrun with correction: %v", got)
+	}
+}
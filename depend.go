@@ -0,0 +1,110 @@
+package cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// dependsSchedule 依赖任务的调度，本身永远不会触发，由上游任务成功结束时触发
+type dependsSchedule struct {
+	mu    sync.Mutex
+	after []int
+	// done 自上一次触发以来已经成功结束的上游任务
+	done map[int]bool
+}
+
+func (d *dependsSchedule) Next(time.Time) time.Time {
+	return time.Time{}
+}
+
+// dependsOn 是否依赖 id
+func (d *dependsSchedule) dependsOn(id int) bool {
+	for _, after := range d.after {
+		if after == id {
+			return true
+		}
+	}
+	return false
+}
+
+// complete 记录上游任务 id 成功结束，所有上游都结束时返回 true 并重新开始计数
+// paused 为 true 时只记录不触发，计数保留到恢复之后
+func (d *dependsSchedule) complete(id int, paused bool) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.done[id] = true
+	if paused {
+		return false
+	}
+	for _, after := range d.after {
+		if !d.done[after] {
+			return false
+		}
+	}
+	d.done = make(map[int]bool)
+	return true
+}
+
+// AddDependentJob 添加依赖其他任务的任务，afterIDs 中的任务都成功结束后执行一次，之后重新等待
+// 任务没有自己的 spec，不会定时触发；上游任务的定时触发、Call 和立即执行都算，
+// 返回错误、panic 或超时的执行不算成功；上游任务的任意一个被删除时该任务也会被删除
+// 暂停时不会被触发，期间上游的完成情况仍然会记录，恢复后任意一个上游再次成功结束时，
+// 如果所有上游都已结束过则执行；afterIDs 为空返回 -1
+func (s *Cron) AddDependentJob(f func(), afterIDs []int, options ...Option) (id int) {
+	id, _ = s.AddDependentJobE(f, afterIDs, options...)
+	return id
+}
+
+// AddDependentJobE 同 AddDependentJob，但会返回失败原因
+// afterIDs 为空返回 ErrNoSpec，其中任意一个任务不存在返回 ErrNotFound
+func (s *Cron) AddDependentJobE(f func(), afterIDs []int, options ...Option) (id int, err error) {
+	if len(afterIDs) == 0 {
+		return -1, ErrNoSpec
+	}
+	ids := make([]string, 0, len(afterIDs))
+	for _, after := range afterIDs {
+		ids = append(ids, strconv.Itoa(after))
+	}
+	spec := "@after " + strings.Join(ids, ",")
+	s.warnStopped(spec)
+
+	s.lock.RLock()
+	for _, after := range afterIDs {
+		if _, ok := s.load(after); !ok {
+			s.lock.RUnlock()
+			return -1, fmt.Errorf("%w: %d", ErrNotFound, after)
+		}
+	}
+	s.lock.RUnlock()
+
+	sched := &dependsSchedule{after: append([]int(nil), afterIDs...), done: make(map[int]bool)}
+	id = s.genID()
+	if err = s.addEntry(id, []string{spec}, []cron.Schedule{sched}, plain(f), applyOptions(options...)); err != nil {
+		return -1, err
+	}
+
+	return id, nil
+}
+
+// triggerDependents 任务 id 成功结束后触发依赖它的任务
+func (s *Cron) triggerDependents(id int) {
+	var ready []int
+	s.lock.RLock()
+	for _, e := range s.relatives(id) {
+		for _, sched := range e.scheds {
+			if d, ok := sched.(*dependsSchedule); ok && d.dependsOn(id) && d.complete(id, e.paused) {
+				ready = append(ready, e.id)
+			}
+		}
+	}
+	s.lock.RUnlock()
+
+	for _, dep := range ready {
+		go s.execute(dep, trigger{source: SourceDependency, at: time.Now()})
+	}
+}
@@ -0,0 +1,101 @@
+package cron
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDependentJobWaitsForAllUpstreams(t *testing.T) {
+	c := NewCron(WithLogger(DiscardLogger))
+	c.Start()
+	defer c.Stop()
+	a := c.AddJob("0 0 9 * * *", func() {})
+	b := c.AddJob("0 0 10 * * *", func() {})
+	ran := make(chan struct{}, 4)
+	dep := c.AddDependentJob(func() { ran <- struct{}{} }, []int{a, b})
+
+	c.Call(a)
+	c.Call(a)
+	never(t, ran)
+	c.Call(b)
+	receive(t, ran)
+	waitIdle(t, c, dep)
+
+	// 执行之后重新等待所有上游
+	c.Call(b)
+	never(t, ran)
+	c.Call(a)
+	receive(t, ran)
+}
+
+func TestDependentJobIgnoresFailedUpstream(t *testing.T) {
+	c := NewCron(WithLogger(DiscardLogger))
+	c.Start()
+	defer c.Stop()
+	fail := true
+	up := c.AddJobE2("0 0 9 * * *", func() error {
+		if fail {
+			return errors.New("boom")
+		}
+		return nil
+	})
+	ran := make(chan struct{}, 2)
+	c.AddDependentJob(func() { ran <- struct{}{} }, []int{up})
+
+	c.Call(up)
+	never(t, ran)
+	fail = false
+	c.Call(up)
+	receive(t, ran)
+}
+
+func TestDependentJobKeepsProgressWhilePaused(t *testing.T) {
+	c := NewCron(WithLogger(DiscardLogger))
+	c.Start()
+	defer c.Stop()
+	a := c.AddJob("0 0 9 * * *", func() {})
+	b := c.AddJob("0 0 10 * * *", func() {})
+	ran := make(chan struct{}, 2)
+	dep := c.AddDependentJob(func() { ran <- struct{}{} }, []int{a, b})
+
+	c.PauseJob(dep)
+	c.Call(a)
+	c.Call(b)
+	never(t, ran)
+
+	// 暂停期间的完成情况保留，恢复后任意一个上游结束即可触发
+	c.ResumeJob(dep)
+	c.Call(a)
+	receive(t, ran)
+	waitIdle(t, c, dep)
+	c.Call(a)
+	never(t, ran)
+}
+
+func TestDependentJobRemovedWithUpstream(t *testing.T) {
+	c := NewCron(WithLogger(DiscardLogger))
+	a := c.AddJob("0 0 9 * * *", func() {})
+	b := c.AddJob("0 0 10 * * *", func() {})
+	dep := c.AddDependentJob(func() {}, []int{a, b})
+	c.RemoveJob(b)
+	if _, ok := c.jobInfo(dep); ok {
+		t.Error("dependent job survived its upstream")
+	}
+	if _, ok := c.jobInfo(a); !ok {
+		t.Error("the other upstream was removed")
+	}
+}
+
+func TestDependentJobErrors(t *testing.T) {
+	c := NewCron(WithLogger(DiscardLogger))
+	if id, err := c.AddDependentJobE(func() {}, nil); id != -1 || !errors.Is(err, ErrNoSpec) {
+		t.Errorf("no upstream: id %d, err %v", id, err)
+	}
+	a := c.AddJob("0 0 9 * * *", func() {})
+	if id, err := c.AddDependentJobE(func() {}, []int{a, 42}); id != -1 || !errors.Is(err, ErrNotFound) {
+		t.Errorf("unknown upstream: id %d, err %v", id, err)
+	}
+	if n := c.Count(); n != 1 {
+		t.Errorf("%d jobs, want only the upstream", n)
+	}
+}
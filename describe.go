@@ -0,0 +1,165 @@
+package cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Describe 返回任务调度的可读描述，比如 "every day at 09:30"
+// 能识别本包辅助方法生成的 spec 以及常见的写法，无法识别时返回原始 spec，
+// spec 带有时区时在描述后用括号注明，比如 "every day at 09:30 (Asia/Shanghai)"，
+// 分组任务的多个描述用 "; " 连接，id 不存在返回空字符串
+func (s *Cron) Describe(id int) string {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	e, ok := s.load(id)
+	if !ok {
+		return ""
+	}
+	out := make([]string, 0, len(e.specs))
+	for _, spec := range e.specs {
+		out = append(out, describeSpec(s.expand(spec)))
+	}
+	return strings.Join(out, "; ")
+}
+
+var descriptors = map[string]string{
+	"@yearly":   "every year on Jan 1 at 00:00",
+	"@annually": "every year on Jan 1 at 00:00",
+	"@monthly":  "every month on day 1 at 00:00",
+	"@weekly":   "every Sunday at 00:00",
+	"@daily":    "every day at 00:00",
+	"@midnight": "every day at 00:00",
+	"@hourly":   "every hour at 00:00",
+}
+
+// describeSpec 生成单个 spec 的描述
+func describeSpec(spec string) string {
+	if d, ok := descriptors[spec]; ok {
+		return d
+	}
+	if tz, fields := splitTZ(spec); tz != "" {
+		return describeSpec(strings.Join(fields, " ")) + " (" + tzName(tz) + ")"
+	}
+	fields := strings.Fields(spec)
+	if len(fields) == 2 {
+		switch fields[0] {
+		case "@every":
+			return "every " + fields[1]
+		case "@every-from-now":
+			return "every " + fields[1] + " from registration"
+		case "@delay":
+			return fields[1] + " after each run finishes"
+		case "@at":
+			return "once at " + fields[1]
+		case "@after":
+			return "after jobs " + strings.ReplaceAll(fields[1], ",", ", ") + " succeed"
+		}
+	}
+	if len(fields) == 3 && fields[0] == "@relative" {
+		if off, err := time.ParseDuration(fields[2]); err == nil {
+			if off < 0 {
+				return fmt.Sprintf("%v before each run of job %s", -off, fields[1])
+			}
+			return fmt.Sprintf("%v after each run of job %s", off, fields[1])
+		}
+	}
+	if len(fields) != 6 {
+		return spec
+	}
+	if d := describeFields(fields); d != "" {
+		return d
+	}
+	return spec
+}
+
+// describeFields 描述六段式 spec，无法识别返回空字符串
+func describeFields(f []string) string {
+	sec, secOK := literal(f[0])
+	min, minOK := literal(f[1])
+	hour, hourOK := literal(f[2])
+	dom, domOK := literal(f[3])
+	dow, dowOK := literal(f[5])
+
+	switch {
+	case f[1] == "*" && f[2] == "*" && allAny(f[3:]):
+		if n, ok := every(f[0]); ok {
+			return plural(n, "second")
+		}
+		if secOK {
+			return fmt.Sprintf("every minute at second %d", sec)
+		}
+	case secOK && f[2] == "*" && allAny(f[3:]):
+		if n, ok := every(f[1]); ok {
+			return fmt.Sprintf("%s at second %d", plural(n, "minute"), sec)
+		}
+		if minOK {
+			return fmt.Sprintf("every hour at %02d:%02d", min, sec)
+		}
+	case secOK && minOK && allAny(f[3:]):
+		if n, ok := every(f[2]); ok {
+			return fmt.Sprintf("%s at %02d:%02d past the hour", plural(n, "hour"), min, sec)
+		}
+		if hourOK {
+			return "every day at " + clock(hour, min, sec)
+		}
+	case secOK && minOK && hourOK && f[4] == "*" && f[5] == "*":
+		if n, ok := every(f[3]); ok {
+			return fmt.Sprintf("%s at %s", plural(n, "day"), clock(hour, min, sec))
+		}
+		if domOK {
+			return fmt.Sprintf("every month on day %d at %s", dom, clock(hour, min, sec))
+		}
+	case secOK && minOK && hourOK && domOK && f[5] == "*":
+		if n, ok := every(f[4]); ok {
+			return fmt.Sprintf("%s on day %d at %s", plural(n, "month"), dom, clock(hour, min, sec))
+		}
+	case secOK && minOK && hourOK && f[3] == "*" && f[4] == "*" && dowOK && dow >= 0 && dow <= 7:
+		return fmt.Sprintf("every %v at %s", time.Weekday(dow%7), clock(hour, min, sec))
+	}
+	return ""
+}
+
+func allAny(fields []string) bool {
+	for _, f := range fields {
+		if f != "*" && f != "?" {
+			return false
+		}
+	}
+	return true
+}
+
+// literal 解析单个数字字段
+func literal(field string) (int, bool) {
+	n, err := strconv.Atoi(field)
+	return n, err == nil
+}
+
+// every 解析 */N 字段，* 视为 */1
+func every(field string) (int, bool) {
+	if field == "*" {
+		return 1, true
+	}
+	if !strings.HasPrefix(field, "*/") {
+		return 0, false
+	}
+	n, err := strconv.Atoi(field[2:])
+	return n, err == nil && n > 0
+}
+
+func plural(n int, unit string) string {
+	if n == 1 {
+		return "every " + unit
+	}
+	return fmt.Sprintf("every %d %ss", n, unit)
+}
+
+// clock 格式化时刻，秒为 0 时省略
+func clock(hour, min, sec int) string {
+	if sec == 0 {
+		return fmt.Sprintf("%02d:%02d", hour, min)
+	}
+	return fmt.Sprintf("%02d:%02d:%02d", hour, min, sec)
+}
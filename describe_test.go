@@ -0,0 +1,85 @@
+package cron
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDescribeSpec(t *testing.T) {
+	tests := []struct {
+		spec, want string
+	}{
+		{"@daily", "every day at 00:00"},
+		{"@hourly", "every hour at 00:00"},
+		{"@weekly", "every Sunday at 00:00"},
+		{"@monthly", "every month on day 1 at 00:00"},
+		{"@yearly", "every year on Jan 1 at 00:00"},
+		{"@every 1m30s", "every 1m30s"},
+		{"@every-from-now 5m0s", "every 5m0s from registration"},
+		{"@delay 10s", "10s after each run finishes"},
+		{"@at 2024-01-01T09:00:00Z", "once at 2024-01-01T09:00:00Z"},
+		{"@after 1,2", "after jobs 1, 2 succeed"},
+		{"@relative 3 5m0s", "5m0s after each run of job 3"},
+		{"@relative 3 -1m0s", "1m0s before each run of job 3"},
+		{"* * * * * *", "every second"},
+		{"*/15 * * * * *", "every 15 seconds"},
+		{"30 * * * * *", "every minute at second 30"},
+		{"0 */5 * * * *", "every 5 minutes at second 0"},
+		{"0 30 * * * *", "every hour at 30:00"},
+		{"15 0 */2 * * *", "every 2 hours at 00:15 past the hour"},
+		{"0 30 9 * * *", "every day at 09:30"},
+		{"15 30 9 * * ?", "every day at 09:30:15"},
+		{"0 0 8 */3 * *", "every 3 days at 08:00"},
+		{"0 0 8 15 * *", "every month on day 15 at 08:00"},
+		{"0 0 8 15 */2 *", "every 2 months on day 15 at 08:00"},
+		{"0 0 18 * * 5", "every Friday at 18:00"},
+		{"0 0 18 * * 7", "every Sunday at 18:00"},
+		{"CRON_TZ=Asia/Shanghai 0 30 9 * * *", "every day at 09:30 (Asia/Shanghai)"},
+		{"TZ=UTC @daily", "every day at 00:00 (UTC)"},
+		// 无法识别的写法返回原始 spec
+		{"0 0 9 * * 1-5", "0 0 9 * * 1-5"},
+		{"0 0,30 * * * *", "0 0,30 * * * *"},
+		{"@every", "@every"},
+	}
+	for _, tt := range tests {
+		if got := describeSpec(tt.spec); got != tt.want {
+			t.Errorf("describeSpec(%q) = %q, want %q", tt.spec, got, tt.want)
+		}
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	c := NewCron(WithLogger(DiscardLogger))
+	noop := func() {}
+	daily, err := c.AddDailyJob(TimeOfDay{Hour: 9, Minute: 30}, noop)
+	if err != nil {
+		t.Fatal(err)
+	}
+	grouped := c.AddGroupedJob([]string{"0 0 9 * * 1", "@every 1h"}, noop)
+	delayed := c.AddFixedDelayJob(10*time.Second, noop)
+
+	tests := []struct {
+		name string
+		id   int
+		want string
+	}{
+		{"helper", daily, "every day at 09:30"},
+		{"grouped", grouped, "every Monday at 09:00; every 1h"},
+		{"delay", delayed, "10s after each run finishes"},
+		{"unknown id", 42, ""},
+	}
+	for _, tt := range tests {
+		if got := c.Describe(tt.id); got != tt.want {
+			t.Errorf("%s: Describe = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDescribeFiveFields(t *testing.T) {
+	// 未开启秒字段时五段式 spec 先补上秒再描述
+	c := NewCron(WithLogger(DiscardLogger), WithoutSeconds())
+	id := c.AddJob("30 9 * * *", func() {})
+	if got := c.Describe(id); got != "every day at 09:30" {
+		t.Errorf("Describe = %q", got)
+	}
+}
@@ -0,0 +1,79 @@
+package cron
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// dispatchWindow 收到触发后等待同一时刻其他触发到达的时间
+// robfig 会为同一时刻到期的每个任务各起一个 goroutine，它们会在极短时间内陆续到达
+const dispatchWindow = 10 * time.Millisecond
+
+type _SingleDispatcher bool
+
+func (d _SingleDispatcher) applyCron(opts *cronOptions) {
+	opts.SingleDispatcher = bool(d)
+}
+
+// WithSingleDispatcher 所有任务的执行都交给同一个 goroutine，
+// 同一时刻触发的任务按 id 从小到大依次执行，执行顺序是确定的，适合测试和有先后依赖的任务
+// 代价是任务之间不再并行：一个慢任务会推迟之后所有任务的执行，
+// Call 和 Immediately 也会经过该 goroutine，因此 Call 不再同步等待任务结束
+func WithSingleDispatcher(d bool) CronOption {
+	return _SingleDispatcher(d)
+}
+
+type dispatch struct {
+	id  int
+	run func()
+}
+
+// dispatcher 将并发到达的触发排序后串行执行
+type dispatcher struct {
+	mu      sync.Mutex
+	pending []dispatch
+	wake    chan struct{}
+}
+
+func newDispatcher() *dispatcher {
+	return &dispatcher{wake: make(chan struct{}, 1)}
+}
+
+// inlineLane 返回 WithAsync(false) 使用的 dispatcher，第一次调用时启动
+func (s *Cron) inlineLane() *dispatcher {
+	s.inlineOnce.Do(func() {
+		s.inline = newDispatcher()
+		go s.inline.loop()
+	})
+	return s.inline
+}
+
+func (d *dispatcher) submit(id int, run func()) {
+	d.mu.Lock()
+	d.pending = append(d.pending, dispatch{id: id, run: run})
+	d.mu.Unlock()
+
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (d *dispatcher) loop() {
+	for range d.wake {
+		time.Sleep(dispatchWindow)
+
+		d.mu.Lock()
+		batch := d.pending
+		d.pending = nil
+		d.mu.Unlock()
+
+		sort.SliceStable(batch, func(i, j int) bool {
+			return batch[i].id < batch[j].id
+		})
+		for _, b := range batch {
+			b.run()
+		}
+	}
+}
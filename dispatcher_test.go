@@ -0,0 +1,142 @@
+package cron
+
+import (
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// orderRecorder 记录任务的执行顺序
+type orderRecorder struct {
+	mu    sync.Mutex
+	order []int
+	ran   chan struct{}
+}
+
+func newOrderRecorder() *orderRecorder {
+	return &orderRecorder{ran: make(chan struct{}, 64)}
+}
+
+func (r *orderRecorder) job(n int) func() {
+	return func() {
+		r.mu.Lock()
+		r.order = append(r.order, n)
+		r.mu.Unlock()
+		r.ran <- struct{}{}
+	}
+}
+
+// take 等待 n 次执行并返回它们的顺序
+func (r *orderRecorder) take(t *testing.T, n int) []int {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		receive(t, r.ran)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := r.order
+	r.order = nil
+	return out
+}
+
+func TestSingleDispatcherScheduledOrder(t *testing.T) {
+	c, clk := newFakeCron(t, WithSingleDispatcher(true))
+	r := newOrderRecorder()
+	var ids []int
+	for i := 0; i < 5; i++ {
+		ids = append(ids, c.AddJob("0 * * * * *", r.job(i)))
+	}
+	c.Start()
+
+	// 每一分钟所有任务同时到期，robfig 式的并发触发到达顺序是随机的
+	for round := 0; round < 3; round++ {
+		blockUntil(t, clk, 1)
+		clk.Advance(time.Minute)
+		if got := r.take(t, len(ids)); !reflect.DeepEqual(got, []int{0, 1, 2, 3, 4}) {
+			t.Fatalf("round %d ran in order %v", round, got)
+		}
+	}
+}
+
+func TestSingleDispatcherSortsByID(t *testing.T) {
+	c, _ := newFakeCron(t, WithSingleDispatcher(true))
+	r := newOrderRecorder()
+	var ids []int
+	for i := 0; i < 4; i++ {
+		ids = append(ids, c.AddJob("0 0 0 1 1 *", r.job(i)))
+	}
+
+	// 按 id 从大到小注入，执行时仍按 id 从小到大
+	at := time.Now()
+	for i := len(ids) - 1; i >= 0; i-- {
+		c.execute(ids[i], trigger{source: SourceSchedule, at: at})
+	}
+	if got := r.take(t, len(ids)); !reflect.DeepEqual(got, []int{0, 1, 2, 3}) {
+		t.Fatalf("ran in order %v, want ascending ids", got)
+	}
+}
+
+func TestSingleDispatcherRunsSerially(t *testing.T) {
+	c, clk := newFakeCron(t, WithSingleDispatcher(true))
+	var running, overlap int32
+	done := make(chan struct{}, 3)
+	for i := 0; i < 3; i++ {
+		c.AddJob("0 * * * * *", func() {
+			if atomic.AddInt32(&running, 1) > 1 {
+				atomic.StoreInt32(&overlap, 1)
+			}
+			time.Sleep(5 * time.Millisecond)
+			atomic.AddInt32(&running, -1)
+			done <- struct{}{}
+		})
+	}
+	c.Start()
+
+	blockUntil(t, clk, 1)
+	clk.Advance(time.Minute)
+	for i := 0; i < 3; i++ {
+		receive(t, done)
+	}
+	if atomic.LoadInt32(&overlap) != 0 {
+		t.Error("jobs overlapped on the single dispatcher")
+	}
+}
+
+func TestSyncJobsShareOneLane(t *testing.T) {
+	c := NewCron(WithLogger(DiscardLogger))
+	c.Start()
+	defer c.Stop()
+	var skips skipRecorder
+	slow, slowStarted, release := blockingJob(t, c)
+	if err := c.ReloadJob(slow, "0 0 9 * * *", WithAsync(false)); err != nil {
+		t.Fatal(err)
+	}
+	ran := make(chan string, 4)
+	queued := c.AddJob("0 0 9 * * *", func() { ran <- "sync" }, WithAsync(false), skips.option())
+	async := c.AddJob("0 0 9 * * *", func() { ran <- "async" })
+
+	at := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	for _, id := range []int{slow, queued, queued, async} {
+		c.execute(id, trigger{source: SourceSchedule, at: at})
+	}
+	receive(t, slowStarted)
+	// 慢的同步任务推迟其他同步任务，异步任务和 Call 不受影响
+	if got := receive(t, ran); got != "async" {
+		t.Fatalf("first run = %s, want async", got)
+	}
+	c.Call(queued)
+	if got := receive(t, ran); got != "sync" {
+		t.Fatalf("Call ran %s", got)
+	}
+	never(t, ran)
+
+	// 同一个同步任务的两次触发依次执行，不会因为与自己重叠而被跳过
+	release()
+	receive(t, ran)
+	receive(t, ran)
+	if got := skips.get(); len(got) != 0 {
+		t.Errorf("skips = %v", got)
+	}
+}
@@ -0,0 +1,49 @@
+package cron
+
+// 执行闸门
+// 所有内置执行策略共用任务上的同一个计数 active，定时触发、立即执行、Call、
+// 依赖触发都经过同一个包装函数进入执行策略，重试发生在一次执行之内，不会再次经过闸门；
+// UpdateJob、ReloadJob 替换执行策略时，正在进行的执行仍然占用闸门，
+// 所以从 ModeJobParallel 切换到 ModeJobSerial 时，新的触发会等旧的执行全部结束后才会执行
+// 绕过闸门的只有 ForceNext
+
+// acquire ModeJobSerial 的闸门，没有正在进行的执行时占用闸门并切换为 StatusRunning，失败返回 false
+// 通过 SetStatus 手动设置为 StatusRunning 时同样不允许执行
+func (s *Cron) acquire(id int) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	e, ok := s.load(id)
+	if !ok || e.active > 0 || e.status == StatusRunning {
+		return false
+	}
+	e.active++
+	e.status = StatusRunning
+	return true
+}
+
+// acquireN 正在执行的次数小于 limit 时加一，任务状态切换为 StatusRunning，失败返回 false
+func (s *Cron) acquireN(id int, limit int) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	e, ok := s.load(id)
+	if !ok || e.active >= limit {
+		return false
+	}
+	e.active++
+	e.status = StatusRunning
+	return true
+}
+
+// release 释放 acquire 或 acquireN 占用的闸门，全部结束时恢复为 StatusReady
+func (s *Cron) release(id int) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	e, ok := s.load(id)
+	if !ok || e.active == 0 {
+		return
+	}
+	e.active--
+	if e.active == 0 {
+		e.status = StatusReady
+	}
+}
@@ -0,0 +1,230 @@
+package cron
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// gateProbe 记录任务的并发情况，任务阻塞到 release 关闭
+type gateProbe struct {
+	running int32
+	max     int32
+	started int32
+	skipped int32
+	release chan struct{}
+}
+
+func newGateProbe() *gateProbe {
+	return &gateProbe{release: make(chan struct{})}
+}
+
+func (p *gateProbe) job() {
+	atomic.AddInt32(&p.started, 1)
+	n := atomic.AddInt32(&p.running, 1)
+	for {
+		m := atomic.LoadInt32(&p.max)
+		if n <= m || atomic.CompareAndSwapInt32(&p.max, m, n) {
+			break
+		}
+	}
+	<-p.release
+	atomic.AddInt32(&p.running, -1)
+}
+
+func (p *gateProbe) onSkip() Option {
+	return WithOnSkip(func(int, string) { atomic.AddInt32(&p.skipped, 1) })
+}
+
+// fire 并发触发 n 次，等到每次触发要么开始执行要么被跳过，返回后执行仍然阻塞
+func (p *gateProbe) fire(t *testing.T, c *Cron, id, n, wantStarted int) {
+	t.Helper()
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if i%2 == 0 {
+				c.execute(id, trigger{source: SourceSchedule, at: time.Now()})
+			} else {
+				_ = c.CallE(id)
+			}
+		}(i)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for int(atomic.LoadInt32(&p.skipped)) != n-wantStarted || int(atomic.LoadInt32(&p.started)) != wantStarted {
+		if time.Now().After(deadline) {
+			t.Fatalf("started %d, skipped %d, want %d started of %d", atomic.LoadInt32(&p.started), atomic.LoadInt32(&p.skipped), wantStarted, n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+	close(p.release)
+	wg.Wait()
+}
+
+func TestGateSerialAcrossCallAndSchedule(t *testing.T) {
+	c := NewCron(WithLogger(DiscardLogger))
+	c.Start()
+	defer c.Stop()
+
+	p := newGateProbe()
+	id := c.AddJob("0 0 9 * * *", p.job, p.onSkip())
+	p.fire(t, c, id, 50, 1)
+	if atomic.LoadInt32(&p.max) != 1 {
+		t.Errorf("max concurrency %d under ModeJobSerial", atomic.LoadInt32(&p.max))
+	}
+	waitIdle(t, c, id)
+}
+
+func TestGateParallelLimit(t *testing.T) {
+	c := NewCron(WithLogger(DiscardLogger))
+	c.Start()
+	defer c.Stop()
+
+	p := newGateProbe()
+	id := c.AddJob("0 0 9 * * *", p.job, WithRunMode(ModeJobParallel), WithMaxConcurrency(3), p.onSkip())
+	p.fire(t, c, id, 40, 3)
+	if atomic.LoadInt32(&p.max) != 3 {
+		t.Errorf("max concurrency %d, want 3", atomic.LoadInt32(&p.max))
+	}
+}
+
+func TestGateQueueDepth(t *testing.T) {
+	c := NewCron(WithLogger(DiscardLogger))
+	c.Start()
+	defer c.Stop()
+
+	p := newGateProbe()
+	id := c.AddJob("0 0 9 * * *", p.job, WithRunMode(ModeJobQueue), WithQueueDepth(2), p.onSkip())
+
+	// 第一次执行占住闸门之后再并发触发，排队的两次在它结束之后依次执行
+	go c.Call(id)
+	for atomic.LoadInt32(&p.started) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.execute(id, trigger{source: SourceSchedule, at: time.Now()})
+		}()
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for atomic.LoadInt32(&p.skipped) != 18 {
+		if time.Now().After(deadline) {
+			t.Fatalf("skipped %d, want 18", atomic.LoadInt32(&p.skipped))
+		}
+		time.Sleep(time.Millisecond)
+	}
+	close(p.release)
+	wg.Wait()
+	if atomic.LoadInt32(&p.started) != 3 || atomic.LoadInt32(&p.max) != 1 {
+		t.Errorf("started %d with max concurrency %d, want 3 runs one at a time", atomic.LoadInt32(&p.started), atomic.LoadInt32(&p.max))
+	}
+}
+
+func TestGateForceNextBypasses(t *testing.T) {
+	c := NewCron(WithLogger(DiscardLogger))
+	c.Start()
+	defer c.Stop()
+
+	p := newGateProbe()
+	id := c.AddJob("0 0 9 * * *", p.job, p.onSkip())
+	go c.Call(id)
+	for atomic.LoadInt32(&p.started) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	// 手动调用不消耗 ForceNext，仍然被跳过
+	c.ForceNext(id)
+	c.execute(id, trigger{source: SourceManual, at: time.Now()})
+	if atomic.LoadInt32(&p.skipped) != 1 {
+		t.Fatalf("manual run was not skipped")
+	}
+
+	done := make(chan struct{})
+	go func() {
+		c.execute(id, trigger{source: SourceSchedule, at: time.Now()})
+		close(done)
+	}()
+	deadline := time.Now().Add(5 * time.Second)
+	for atomic.LoadInt32(&p.started) != 2 {
+		if time.Now().After(deadline) {
+			t.Fatal("forced run did not start")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if atomic.LoadInt32(&p.max) != 2 {
+		t.Errorf("max concurrency %d, want 2 with ForceNext", atomic.LoadInt32(&p.max))
+	}
+
+	// 标记只生效一次
+	c.execute(id, trigger{source: SourceSchedule, at: time.Now()})
+	if atomic.LoadInt32(&p.skipped) != 2 {
+		t.Errorf("second scheduled run was not skipped")
+	}
+	close(p.release)
+	receive(t, done)
+}
+
+func TestGateSwitchToSerialWaitsForInflight(t *testing.T) {
+	c := NewCron(WithLogger(DiscardLogger))
+	c.Start()
+	defer c.Stop()
+
+	p := newGateProbe()
+	id := c.AddJob("0 0 9 * * *", p.job, WithRunMode(ModeJobParallel), WithMaxConcurrency(2), p.onSkip())
+	go c.Call(id)
+	go c.Call(id)
+	for atomic.LoadInt32(&p.started) != 2 {
+		time.Sleep(time.Millisecond)
+	}
+
+	if err := c.UpdateJob(id, "0 0 9 * * *", p.job, p.onSkip()); err != nil {
+		t.Fatal(err)
+	}
+	c.execute(id, trigger{source: SourceSchedule, at: time.Now()})
+	if atomic.LoadInt32(&p.skipped) != 1 {
+		t.Errorf("serial run started while parallel runs were in flight")
+	}
+	close(p.release)
+}
+
+func TestGateAcquireRace(t *testing.T) {
+	c := NewCron(WithLogger(DiscardLogger))
+	id := c.AddJob("0 0 9 * * *", func() {})
+
+	var won, wonN int32
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			if c.acquire(id) {
+				atomic.AddInt32(&won, 1)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			if c.acquireN(id, 5) {
+				atomic.AddInt32(&wonN, 1)
+			}
+		}()
+	}
+	wg.Wait()
+	if won > 1 || won+wonN > 5 || won+wonN == 0 {
+		t.Fatalf("acquire won %d, acquireN won %d", won, wonN)
+	}
+	for i := int32(0); i < won+wonN; i++ {
+		c.release(id)
+	}
+	if c.GetStatus(id) != StatusReady {
+		t.Errorf("status %d after releasing everything", c.GetStatus(id))
+	}
+	c.release(id)
+	if !c.acquire(id) {
+		t.Error("extra release broke the gate")
+	}
+}
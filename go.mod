@@ -2,4 +2,8 @@ module github.com/kainhuck/cron
 
 go 1.18
 
-require github.com/robfig/cron/v3 v3.0.1 // indirect
+require (
+	github.com/robfig/cron/v3 v3.0.1
+	golang.org/x/time v0.10.0
+	gopkg.in/yaml.v3 v3.0.1
+)
@@ -0,0 +1,57 @@
+package cron
+
+type _Group string
+
+func (g _Group) apply(opts *options) {
+	opts.Group = string(g)
+}
+
+// WithGroup 设置任务所属的分组，之后可以通过 PauseGroup、RemoveGroup 等方法整组操作
+// 分组不要求唯一，ReloadJob、UpdateJob 替换配置时分组也一起替换
+func WithGroup(group string) Option {
+	return _Group(group)
+}
+
+// AddJobToGroup 添加属于 group 的任务，等同于 AddJobE 加上 WithGroup(group)
+func (s *Cron) AddJobToGroup(group, spec string, f func(), options ...Option) (id int, err error) {
+	return s.AddJobE(spec, f, append(options, WithGroup(group))...)
+}
+
+// PauseGroup 暂停分组内的所有任务，返回实际暂停的数量，见 PauseJob
+func (s *Cron) PauseGroup(group string) int {
+	return s.PauseWhere(inGroup(group))
+}
+
+// ResumeGroup 恢复分组内所有已暂停的任务，返回实际恢复的数量，见 ResumeJob
+func (s *Cron) ResumeGroup(group string) int {
+	return s.ResumeWhere(inGroup(group))
+}
+
+// RemoveGroup 删除分组内的所有任务，返回删除的数量，见 RemoveJob
+func (s *Cron) RemoveGroup(group string) int {
+	n := 0
+	for _, st := range s.snapshot() {
+		if st.Group == group {
+			s.RemoveJob(st.ID)
+			n++
+		}
+	}
+	return n
+}
+
+// ListGroup 返回分组内所有任务的概要信息，按 id 排序
+func (s *Cron) ListGroup(group string) []JobInfo {
+	var out []JobInfo
+	for _, info := range s.ListJobs() {
+		if info.Group == group {
+			out = append(out, info)
+		}
+	}
+	return out
+}
+
+func inGroup(group string) func(JobStats) bool {
+	return func(st JobStats) bool {
+		return st.Group == group
+	}
+}
@@ -0,0 +1,78 @@
+package cron
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ScheduleHash 返回任务调度配置的哈希，id 不存在返回空字符串
+// 由 spec 和影响调度、执行行为的配置决定，相同输入在进程重启后得到相同结果，
+// 可用于重新加载配置时判断任务是否发生变化
+// 注意：开启 Random 的辅助方法每次生成的 spec 不同，哈希也会不同
+func (s *Cron) ScheduleHash(id int) string {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	entryI, ok := s.entry.Load(id)
+	if !ok {
+		return ""
+	}
+	e := entryI.(*entry)
+	return scheduleHash(e.specs, e.opt)
+}
+
+// scheduleHash 按固定顺序序列化 spec 和配置后计算 sha256
+// 回调、日志、中间件等只观察执行的配置不参与计算；其他函数和接口类型的配置无法比较，只计算是否设置以及具体类型
+// 新增配置时需要同时加到这里，TestScheduleHashCoversOptions 会检查
+func scheduleHash(specs []string, opt options) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "specs=%s\n", strings.Join(specs, "\x00"))
+	fmt.Fprintf(h, "run_mode=%d\n", opt.RunMode)
+	fmt.Fprintf(h, "immediately=%t\n", opt.Immediately)
+	fmt.Fprintf(h, "random=%t\n", opt.Random)
+	fmt.Fprintf(h, "recover=%t\n", opt.Recover)
+	fmt.Fprintf(h, "drift_correction=%t\n", opt.DriftCorrection)
+	fmt.Fprintf(h, "strategy=%T\n", opt.Strategy)
+	fmt.Fprintf(h, "name=%q\n", opt.Name)
+	fmt.Fprintf(h, "group=%q\n", opt.Group)
+	fmt.Fprintf(h, "func=%q\n", opt.Func)
+	fmt.Fprintf(h, "payload=%s\n", payloadHash(opt))
+	fmt.Fprintf(h, "mutex=%q/%d\n", opt.MutexGroup, opt.MutexPolicy)
+	fmt.Fprintf(h, "async=%t\n", opt.Async)
+	fmt.Fprintf(h, "timeout=%d\n", opt.Timeout)
+	fmt.Fprintf(h, "hard_timeout=%d/%t\n", opt.HardTimeout, opt.ReleaseOnAbandon)
+	if w := opt.RandomWindow; w != nil {
+		fmt.Fprintf(h, "random_window=%d/%d\n", w.anchor, w.spread)
+	}
+	fmt.Fprintf(h, "history_size=%d\n", opt.HistorySize)
+	fmt.Fprintf(h, "manual_backlog=%d\n", opt.ManualBacklog)
+	fmt.Fprintf(h, "idempotency=%t/%d\n", opt.IdempotencyKey != nil, opt.IdempotencyTTL)
+	if l := opt.RateLimiter; l != nil {
+		fmt.Fprintf(h, "rate_limit=%v/%d\n", l.Limit(), l.Burst())
+	}
+	fmt.Fprintf(h, "retry=%d/%T/%v\n", opt.RetryMax, opt.RetryBackoff, opt.RetryBackoff)
+	fmt.Fprintf(h, "lock=%T/%d\n", opt.Locker, opt.LockTTL)
+	fmt.Fprintf(h, "max_concurrency=%d\n", opt.MaxConcurrency)
+	fmt.Fprintf(h, "queue_depth=%d\n", opt.QueueDepth)
+	fmt.Fprintf(h, "jitter=%d\n", opt.Jitter)
+	if opt.Timezone != nil {
+		fmt.Fprintf(h, "timezone=%s\n", opt.Timezone)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// payloadHash AddTypedJob 的参数参与计算的形式，无法编码为 JSON 时只计算类型
+func payloadHash(opt options) string {
+	if opt.PayloadJSON != nil {
+		return string(opt.PayloadJSON)
+	}
+	if opt.Payload == nil {
+		return ""
+	}
+	if data, err := json.Marshal(opt.Payload); err == nil {
+		return string(data)
+	}
+	return fmt.Sprintf("%T", opt.Payload)
+}
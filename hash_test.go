@@ -0,0 +1,120 @@
+package cron
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// unhashed 不参与 scheduleHash 的配置，只观察执行，不改变调度和执行行为
+var unhashed = map[string]bool{
+	"OnSkip":         true,
+	"OnSkipAt":       true,
+	"PanicHandler":   true,
+	"TimeoutHandler": true,
+	"ErrorHandler":   true,
+	"OnError":        true,
+	"BeforeRun":      true,
+	"AfterRun":       true,
+	"Logger":         true,
+	"Middlewares":    true,
+	"Hooks":          true,
+	"Metrics":        true,
+	"AuditWriters":   true,
+}
+
+type hashLocker struct{}
+
+func (hashLocker) TryLock(context.Context, string, time.Duration) (bool, error) { return true, nil }
+
+func (hashLocker) Unlock(context.Context, string) error { return nil }
+
+// mutate 返回与 v 不同的值，不知道如何修改时返回 false
+func mutate(name string, v reflect.Value) (reflect.Value, bool) {
+	switch name {
+	case "Strategy":
+		return reflect.ValueOf(timeFirstStrategy{}), true
+	case "RandomWindow":
+		return reflect.ValueOf(&randomWindow{anchor: time.Minute, spread: time.Second}), true
+	case "IdempotencyKey":
+		return reflect.ValueOf(func(time.Time) string { return "" }), true
+	case "RateLimiter":
+		return reflect.ValueOf(rate.NewLimiter(1, 1)), true
+	case "RetryBackoff":
+		return reflect.ValueOf(ConstantBackoff(time.Second)), true
+	case "Locker":
+		return reflect.ValueOf(hashLocker{}), true
+	case "Timezone":
+		return reflect.ValueOf(time.FixedZone("X", 3600)), true
+	case "Payload":
+		return reflect.ValueOf(42), true
+	case "PayloadJSON":
+		return reflect.ValueOf(json.RawMessage("42")), true
+	}
+	out := reflect.New(v.Type()).Elem()
+	switch v.Kind() {
+	case reflect.Bool:
+		out.SetBool(!v.Bool())
+	case reflect.Int, reflect.Int64:
+		out.SetInt(v.Int() + 1)
+	case reflect.Uint:
+		out.SetUint(v.Uint() + 1)
+	case reflect.String:
+		out.SetString(v.String() + "x")
+	default:
+		return out, false
+	}
+	return out, true
+}
+
+func TestScheduleHashCoversOptions(t *testing.T) {
+	specs := []string{"0 0 9 * * *"}
+	base := scheduleHash(specs, defaultOpt)
+	typ := reflect.TypeOf(defaultOpt)
+	for i := 0; i < typ.NumField(); i++ {
+		name := typ.Field(i).Name
+		if unhashed[name] {
+			continue
+		}
+		opt := defaultOpt
+		field := reflect.ValueOf(&opt).Elem().Field(i)
+		v, ok := mutate(name, field)
+		if !ok {
+			t.Errorf("options.%s: unknown kind %s, add it to mutate or unhashed", name, field.Kind())
+			continue
+		}
+		field.Set(v)
+		if scheduleHash(specs, opt) == base {
+			t.Errorf("options.%s does not change scheduleHash", name)
+		}
+	}
+}
+
+func TestScheduleHashStable(t *testing.T) {
+	c := NewCron(WithLogger(DiscardLogger))
+	a := c.AddJob("0 0 9 * * *", func() {}, WithName("a"), WithTimeout(time.Minute))
+	b := c.AddJob("0 0 9 * * *", func() {}, WithName("b"), WithTimeout(time.Minute))
+	same := c.AddJob("0 0 9 * * *", func() {}, WithTimeout(time.Minute), WithName("c"))
+
+	if c.ScheduleHash(a) == c.ScheduleHash(b) {
+		t.Error("jobs with different names hash the same")
+	}
+	if got, want := c.ScheduleHash(same), scheduleHash([]string{"0 0 9 * * *"}, applyOptions(WithName("c"), WithTimeout(time.Minute))); got != want {
+		t.Errorf("hash depends on option order: %s != %s", got, want)
+	}
+	if c.ScheduleHash(-1) != "" {
+		t.Error("unknown id should hash to an empty string")
+	}
+
+	before := c.ScheduleHash(a)
+	if err := c.ReloadJob(a, "0 0 9 * * *", WithName("a"), WithTimeout(2*time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	if c.ScheduleHash(a) == before {
+		t.Error("changing Timeout does not change the hash")
+	}
+}
@@ -0,0 +1,223 @@
+package cron
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Outcome 一次执行的结果
+type Outcome string
+
+const (
+	// OutcomeSuccess 正常结束
+	OutcomeSuccess Outcome = "success"
+	// OutcomeError 任务返回了错误
+	OutcomeError Outcome = "error"
+	// OutcomePanic 执行中发生了 panic
+	OutcomePanic Outcome = "panic"
+	// OutcomeTimeout 执行耗时超过了 WithTimeout
+	OutcomeTimeout Outcome = "timeout"
+	// OutcomeSkipped 触发被跳过，没有执行
+	OutcomeSkipped Outcome = "skipped"
+)
+
+// RunRecord 一次执行的记录，被跳过的触发同样会记录
+type RunRecord struct {
+	// Start 开始时间，跳过时为触发被跳过的时间
+	Start time.Time
+	// Duration 执行耗时
+	Duration time.Duration
+	// Panic 执行中 panic 的值，正常结束为 nil
+	Panic interface{}
+	// Stack 发生 panic 时的调用栈，正常结束为 nil
+	Stack []byte
+	// TimedOut 执行耗时是否超过了 WithTimeout
+	TimedOut bool
+	// Outcome 执行结果，同时发生 panic 和超时时为 OutcomePanic
+	Outcome Outcome
+	// Error 错误信息，成功和跳过时为空
+	Error string
+	// SkipReason 跳过的原因，为 SkipReason* 常量之一，只在 OutcomeSkipped 时有值
+	SkipReason string
+}
+
+// outcome 根据执行情况得出结果
+func outcome(err error, panicked, timedOut bool) Outcome {
+	switch {
+	case panicked:
+		return OutcomePanic
+	case timedOut:
+		return OutcomeTimeout
+	case err != nil:
+		return OutcomeError
+	default:
+		return OutcomeSuccess
+	}
+}
+
+type _HistorySize int
+
+func (n _HistorySize) apply(opts *options) {
+	opts.HistorySize = int(n)
+}
+
+// WithHistorySize 设置保留的执行记录条数，0 表示不记录
+func WithHistorySize(n int) Option {
+	return _HistorySize(n)
+}
+
+// history 固定容量的环形缓冲区
+type history struct {
+	mu   sync.Mutex
+	buf  []RunRecord
+	next int
+	full bool
+}
+
+func newHistory(size int) *history {
+	h := &history{}
+	h.resize(size)
+	return h
+}
+
+// resize 调整容量，保留最近的记录，size 为 0 时不再记录
+func (h *history) resize(size int) {
+	if size < 0 {
+		size = 0
+	}
+	kept := h.last(size)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.buf = make([]RunRecord, size)
+	h.next, h.full = 0, false
+	for _, r := range kept {
+		h.add(r)
+	}
+}
+
+// add 追加一条记录，调用方需持有 h.mu
+func (h *history) add(r RunRecord) {
+	if len(h.buf) == 0 {
+		return
+	}
+	h.buf[h.next] = r
+	h.next = (h.next + 1) % len(h.buf)
+	if h.next == 0 {
+		h.full = true
+	}
+}
+
+func (h *history) append(r RunRecord) {
+	h.mu.Lock()
+	h.add(r)
+	h.mu.Unlock()
+}
+
+// last 按时间顺序返回最近 k 条记录，k 小于 0 时返回全部
+func (h *history) last(k int) []RunRecord {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	n := h.next
+	if h.full {
+		n = len(h.buf)
+	}
+	if k > n || k < 0 {
+		k = n
+	}
+	if k <= 0 {
+		return nil
+	}
+	out := make([]RunRecord, 0, k)
+	for i := k; i > 0; i-- {
+		out = append(out, h.buf[(h.next-i+len(h.buf))%len(h.buf)])
+	}
+	return out
+}
+
+// History 按时间顺序返回任务最近 k 次的执行记录，k 不大于 0 时返回保留的全部记录，
+// 保留的条数见 WithHistorySize；id 不存在或未开启记录时返回 nil
+func (s *Cron) History(id int, k int) []RunRecord {
+	e, ok := s.load(id)
+	if !ok {
+		return nil
+	}
+	if k <= 0 {
+		k = -1
+	}
+	return e.history.last(k)
+}
+
+// record 包装任务函数，记录每次执行的开始时间、结果和执行记录，并调用生命周期回调；
+// panic 会在记录后继续向上抛出
+// 超过 WithTimeout 的执行在结束时记为失败，没有其他错误时 LastError 为 ErrTimeout
+func (s *Cron) record(id int, f jobFunc) execFunc {
+	return func(t trigger) {
+		start := time.Now()
+		e, opt, ok := s.loadOptions(id)
+		if !ok {
+			return
+		}
+		atomic.StoreInt64(&e.counters.lastRun, start.UnixNano())
+		atomic.AddUint64(&e.counters.runs, 1)
+		atomic.AddInt64(&e.counters.inflight, 1)
+		key := e.starts.add(start)
+		s.eachHooks(opt, func(h Hooks) {
+			if h.OnStart != nil {
+				h.OnStart(id)
+			}
+		})
+		var err error
+		defer func() {
+			r := recover()
+			d := time.Since(start)
+			atomic.AddInt64(&e.counters.inflight, -1)
+			e.starts.remove(key)
+			atomic.StoreInt64(&e.counters.lastDuration, int64(d))
+			if r != nil {
+				err = &PanicError{Value: r}
+			}
+			timedOut := opt.Timeout > 0 && d > opt.Timeout
+			if timedOut && err == nil {
+				err = ErrTimeout
+			}
+			if err != nil {
+				atomic.AddUint64(&e.counters.failures, 1)
+			} else {
+				atomic.AddUint64(&e.counters.successes, 1)
+			}
+			e.result.set(err)
+			if err != nil && opt.OnError != nil {
+				opt.OnError(id, err)
+			}
+			rec := RunRecord{Start: start, Duration: d, Panic: r, TimedOut: timedOut, Outcome: outcome(err, r != nil, timedOut)}
+			if err != nil {
+				rec.Error = err.Error()
+			}
+			if r != nil {
+				rec.Stack = stack()
+			}
+			e.history.append(rec)
+			s.eachHooks(opt, func(h Hooks) {
+				if h.OnComplete != nil {
+					h.OnComplete(id, d, err)
+				}
+				if r != nil && h.OnPanic != nil {
+					h.OnPanic(id, r)
+				}
+			})
+			if r != nil {
+				panic(r)
+			}
+			if err == nil {
+				s.triggerDependents(id)
+			}
+		}()
+		s.lock.RLock()
+		ctx := s.root
+		s.lock.RUnlock()
+		err = f(context.WithValue(ctx, sourceKey{}, t.source))
+	}
+}
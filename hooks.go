@@ -0,0 +1,65 @@
+package cron
+
+import "time"
+
+type _BeforeRun func(id int)
+
+func (f _BeforeRun) apply(opts *options) {
+	opts.BeforeRun = f
+}
+
+// WithBeforeRun 设置每次执行前的回调，在执行策略修改任务状态之前调用
+// 被执行策略跳过的触发同样会调用，回调之间的顺序为
+// BeforeRun -> 状态变为运行中 -> 任务函数 -> 状态恢复 -> AfterRun
+func WithBeforeRun(f func(id int)) Option {
+	return _BeforeRun(f)
+}
+
+type _AfterRun func(id int, d time.Duration)
+
+func (f _AfterRun) apply(opts *options) {
+	opts.AfterRun = f
+}
+
+// WithAfterRun 设置每次执行后的回调，d 为从 BeforeRun 开始的耗时，任务 panic 时同样会调用
+func WithAfterRun(f func(id int, d time.Duration)) Option {
+	return _AfterRun(f)
+}
+
+// hooks 调度器级别的默认回调，任务没有设置时使用
+type hooks struct {
+	before func(id int)
+	after  func(id int, d time.Duration)
+}
+
+// SetDefaultHooks 设置所有任务默认的 BeforeRun 和 AfterRun，任务自己设置的回调优先
+// 对已经添加的任务同样生效，传 nil 表示不设置
+func (s *Cron) SetDefaultHooks(before func(id int), after func(id int, d time.Duration)) {
+	s.lock.Lock()
+	s.hooks = hooks{before: before, after: after}
+	s.lock.Unlock()
+}
+
+// withHooks 在 run 前后调用回调
+func (s *Cron) withHooks(id int, run func(), opt options) func() {
+	return func() {
+		s.lock.RLock()
+		h := s.hooks
+		s.lock.RUnlock()
+		if opt.BeforeRun != nil {
+			h.before = opt.BeforeRun
+		}
+		if opt.AfterRun != nil {
+			h.after = opt.AfterRun
+		}
+
+		start := time.Now()
+		if h.before != nil {
+			h.before(id)
+		}
+		if h.after != nil {
+			defer func() { h.after(id, time.Since(start)) }()
+		}
+		run()
+	}
+}
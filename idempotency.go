@@ -0,0 +1,118 @@
+package cron
+
+import (
+	"sync"
+	"time"
+)
+
+type _IdempotencyKey func(scheduledTime time.Time) string
+
+func (f _IdempotencyKey) apply(opts *options) {
+	opts.IdempotencyKey = f
+}
+
+// WithIdempotencyKey 按触发时间计算幂等键，相同的键在 WithIdempotencyTTL 时间内只会执行一次
+// 适用于同一个逻辑时刻可能被多个来源触发的任务（比如定时触发和手动 Call 同时发生），
+// 与按间隔限制不同，它按触发的身份去重
+// 键在执行策略放行之后才占用，被 ModeJobSerial 等执行策略或互斥组跳过的触发不会占用键
+// scheduledTime 为本次触发的时间，定时触发时与计划时间只有极小的误差，
+// 通常应在 key 中按任务周期截断，比如 t.Truncate(time.Minute).Format(time.RFC3339)
+func WithIdempotencyKey(f func(scheduledTime time.Time) string) Option {
+	return _IdempotencyKey(f)
+}
+
+type _IdempotencyTTL time.Duration
+
+func (d _IdempotencyTTL) apply(opts *options) {
+	opts.IdempotencyTTL = time.Duration(d)
+}
+
+// WithIdempotencyTTL 设置幂等键的保留时间
+func WithIdempotencyTTL(d time.Duration) Option {
+	return _IdempotencyTTL(d)
+}
+
+// idempotency 记录最近执行过的幂等键
+type idempotency struct {
+	mu   sync.Mutex
+	key  func(time.Time) string
+	keys map[string]time.Time
+}
+
+func newIdempotency(key func(time.Time) string) *idempotency {
+	d := &idempotency{}
+	d.setKey(key)
+	return d
+}
+
+// setKey 替换计算幂等键的函数并清空已记录的键，key 为 nil 时不去重
+func (d *idempotency) setKey(key func(time.Time) string) {
+	d.mu.Lock()
+	d.key = key
+	d.keys = make(map[string]time.Time)
+	d.mu.Unlock()
+}
+
+// claim 键未在 ttl 内出现过时记录并返回 true
+func (d *idempotency) claim(at time.Time, ttl time.Duration) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.key == nil {
+		return true
+	}
+	key := d.key(at)
+	now := time.Now()
+	for k, t := range d.keys {
+		if now.Sub(t) >= ttl {
+			delete(d.keys, k)
+		}
+	}
+	if _, ok := d.keys[key]; ok {
+		return false
+	}
+	d.keys[key] = now
+	return true
+}
+
+// release 撤销 claim 记录的键，用于占用了键之后又被跳过的执行
+func (d *idempotency) release(at time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.key != nil {
+		delete(d.keys, d.key(at))
+	}
+}
+
+// claim 在执行策略放行之后占用本次触发的幂等键，键已被占用时跳过本次执行
+// 被执行策略跳过的触发不会占用键，之后同一时刻的触发仍然可以执行
+func (s *Cron) claim(id int, t trigger) bool {
+	e, opt, ok := s.loadOptions(id)
+	if !ok {
+		return false
+	}
+	if !e.seen.claim(t.at, opt.IdempotencyTTL) {
+		s.skip(id, SkipReasonIdempotency, t.at)
+		return false
+	}
+	return true
+}
+
+// unclaim 撤销 claim，执行在执行策略之后被跳过时调用
+func (s *Cron) unclaim(id int, t trigger) {
+	if e, ok := s.load(id); ok {
+		e.seen.release(t.at)
+	}
+}
+
+// admit 判断本次触发是否应该执行，不执行时触发 OnSkip
+func (s *Cron) admit(id int, t trigger) bool {
+	_, opt, ok := s.loadOptions(id)
+	if !ok {
+		return false
+	}
+	if opt.RateLimiter != nil && !opt.RateLimiter.Allow() {
+		s.skip(id, SkipReasonRateLimit, t.at)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,152 @@
+package cron
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// skipRecorder 记录 OnSkip 的 reason
+type skipRecorder struct {
+	mu      sync.Mutex
+	reasons []string
+}
+
+func (r *skipRecorder) option() Option {
+	return WithOnSkip(func(_ int, reason string) {
+		r.mu.Lock()
+		r.reasons = append(r.reasons, reason)
+		r.mu.Unlock()
+	})
+}
+
+func (r *skipRecorder) get() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.reasons...)
+}
+
+func byTime(t time.Time) string { return t.Format(time.RFC3339Nano) }
+
+func TestIdempotencyKeyNotConsumedBySerialSkip(t *testing.T) {
+	c := NewCron(WithLogger(DiscardLogger))
+	c.Start()
+	defer c.Stop()
+
+	var skips skipRecorder
+	block := make(chan struct{})
+	ran := make(chan time.Time, 10)
+	id := c.AddJobContext("0 0 9 * * *", func(ctx context.Context) {
+		ran <- time.Now()
+		<-block
+	}, WithIdempotencyKey(byTime), skips.option())
+
+	// 手动执行占住 ModeJobSerial 的闸门
+	if !c.CallAsync(id) {
+		t.Fatal("manual run did not start")
+	}
+	receive(t, ran)
+
+	at := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	c.execute(id, trigger{source: SourceSchedule, at: at})
+	if got := skips.get(); len(got) != 1 || got[0] != SkipReasonSerial {
+		t.Fatalf("skips = %v, want [serial]", got)
+	}
+
+	close(block)
+	waitIdle(t, c, id)
+
+	// 同一时刻的再次触发不应被当作重复
+	c.execute(id, trigger{source: SourceSchedule, at: at})
+	receive(t, ran)
+	c.execute(id, trigger{source: SourceSchedule, at: at})
+	never(t, ran)
+	if got := skips.get(); len(got) != 2 || got[1] != SkipReasonIdempotency {
+		t.Fatalf("skips = %v, want [serial idempotency]", got)
+	}
+}
+
+func TestIdempotencyKeyNotConsumedByMutexSkip(t *testing.T) {
+	c := NewCron(WithLogger(DiscardLogger))
+	c.Start()
+	defer c.Stop()
+
+	block := make(chan struct{})
+	ran := make(chan struct{}, 10)
+	holder := c.AddJob("0 0 9 * * *", func() {
+		ran <- struct{}{}
+		<-block
+	}, WithMutexGroup("db", MutexSkip))
+	id := c.AddJob("0 0 9 * * *", func() { ran <- struct{}{} }, WithMutexGroup("db", MutexSkip), WithIdempotencyKey(byTime))
+
+	c.CallAsync(holder)
+	receive(t, ran)
+	at := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	c.execute(id, trigger{source: SourceSchedule, at: at})
+	never(t, ran)
+
+	close(block)
+	waitIdle(t, c, holder)
+	c.execute(id, trigger{source: SourceSchedule, at: at})
+	receive(t, ran)
+}
+
+// scriptedLocker 按顺序返回 results 中的结果，用完之后总能拿到锁
+type scriptedLocker struct {
+	mu      sync.Mutex
+	results []error
+}
+
+// errLockHeld 让 scriptedLocker 返回 false 和 nil
+var errLockHeld = errors.New("lock held")
+
+func (l *scriptedLocker) TryLock(context.Context, string, time.Duration) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(l.results) == 0 {
+		return true, nil
+	}
+	err := l.results[0]
+	l.results = l.results[1:]
+	if err == errLockHeld {
+		return false, nil
+	}
+	return false, err
+}
+
+func (l *scriptedLocker) Unlock(context.Context, string) error { return nil }
+
+func TestIdempotencyKeyNotConsumedByLockMiss(t *testing.T) {
+	c := NewCron(WithLogger(DiscardLogger))
+	c.Start()
+	defer c.Stop()
+
+	var skips skipRecorder
+	failed := make(chan error, 1)
+	locker := &scriptedLocker{results: []error{errLockHeld, errors.New("redis down")}}
+	ran := make(chan struct{}, 10)
+	id := c.AddJob("0 0 9 * * *", func() { ran <- struct{}{} },
+		WithDistributedLock(locker), WithIdempotencyKey(byTime), skips.option(),
+		WithErrorHandler(func(_ int, _ int, err error) { failed <- err }))
+
+	// 锁被其他实例持有和获取锁出错都不占用幂等键
+	at := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	c.execute(id, trigger{source: SourceSchedule, at: at})
+	never(t, ran)
+	if got := skips.get(); len(got) != 1 || got[0] != SkipReasonLock {
+		t.Fatalf("skips = %v, want [lock]", got)
+	}
+	c.execute(id, trigger{source: SourceSchedule, at: at})
+	receive(t, failed)
+	never(t, ran)
+
+	c.execute(id, trigger{source: SourceSchedule, at: at})
+	receive(t, ran)
+	c.execute(id, trigger{source: SourceSchedule, at: at})
+	never(t, ran)
+	if got := skips.get(); len(got) != 2 || got[1] != SkipReasonIdempotency {
+		t.Fatalf("skips = %v, want [lock idempotency]", got)
+	}
+}
@@ -0,0 +1,36 @@
+package cron
+
+import "time"
+
+type _Jitter time.Duration
+
+func (d _Jitter) apply(opts *options) {
+	opts.Jitter = time.Duration(d)
+}
+
+// WithJitter 每次执行前随机等待 [0, max)，让同一时刻触发的任务错开执行
+// 与 WithRandom 只在添加任务时随机选择 spec 不同，它对每次执行都生效，
+// 对任何 spec 和辅助方法（包括 AddSecondJob）效果一致，是削峰的推荐方式
+// 等待期间任务已处于运行状态，ModeJobSerial 下这段时间内的触发同样会被跳过；
+// 等待期间调度器 Stop 时本次执行会被放弃
+func WithJitter(max time.Duration) Option {
+	return _Jitter(max)
+}
+
+// jitter 包装任务函数，执行前随机等待
+func (s *Cron) jitter(f execFunc, max time.Duration) execFunc {
+	return func(t trigger) {
+		s.lock.RLock()
+		done := s.root.Done()
+		s.lock.RUnlock()
+
+		timer := time.NewTimer(time.Duration(s.rand.Int63n(int64(max))))
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-done:
+			return
+		}
+		f(t)
+	}
+}
@@ -0,0 +1,54 @@
+package cron
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestJitterDelaysEachRun(t *testing.T) {
+	c := NewCron(WithRandSource(rand.NewSource(42)), WithLogger(DiscardLogger))
+	ran := make(chan time.Time, 1)
+	id := c.AddJob("0 0 9 * * *", func() { ran <- time.Now() }, WithJitter(20*time.Millisecond))
+
+	delays := map[time.Duration]bool{}
+	for i := 0; i < 10; i++ {
+		start := time.Now()
+		c.Call(id)
+		d := receive(t, ran).Sub(start)
+		if d >= 40*time.Millisecond {
+			t.Errorf("run %d waited %v, longer than the 20ms jitter allows", i, d)
+		}
+		delays[d.Round(time.Millisecond)] = true
+	}
+	// 每次执行单独随机
+	if len(delays) < 2 {
+		t.Errorf("all runs waited the same: %v", delays)
+	}
+}
+
+func TestJitterCountsAsRunning(t *testing.T) {
+	c := NewCron(WithLogger(DiscardLogger))
+	c.Start()
+	var skips skipRecorder
+	ran := make(chan struct{}, 2)
+	id := c.AddJob("0 0 9 * * *", func() { ran <- struct{}{} }, WithJitter(time.Hour), skips.option())
+
+	if !c.CallAsync(id) {
+		t.Fatal("run did not start")
+	}
+	// 等待期间已处于运行状态，ModeJobSerial 下的触发被跳过
+	if got := c.GetStatus(id); got != StatusRunning {
+		t.Errorf("status = %d, want StatusRunning", got)
+	}
+	if c.CallAsync(id) {
+		t.Error("second run started during the jitter")
+	}
+	if got := skips.get(); len(got) != 1 || got[0] != SkipReasonSerial {
+		t.Errorf("skips = %v", got)
+	}
+
+	// Stop 时放弃等待中的执行
+	receive(t, c.Stop().Done())
+	never(t, ran)
+}
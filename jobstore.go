@@ -0,0 +1,305 @@
+package cron
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ErrUnknownFunc 任务函数没有通过 RegisterFunc 注册
+var ErrUnknownFunc = errors.New("cron: function not registered")
+
+// JobRecord JobStore 保存的任务
+// 任务函数无法序列化，保存的是 RegisterFunc 注册时使用的函数名，重启后按名字重新绑定
+type JobRecord struct {
+	// Name 任务名，同时作为记录的 key
+	Name string `json:"name"`
+	// Func 任务函数注册时的名字
+	Func string `json:"func"`
+	// Spec 任务的 spec
+	Spec string `json:"spec"`
+	// Group 见 WithGroup
+	Group string `json:"group,omitempty"`
+	// RunMode 运行模式
+	RunMode RunMode `json:"run_mode"`
+	// Timeout 见 WithTimeout
+	Timeout time.Duration `json:"timeout,omitempty"`
+	// Payload AddStoredTypedJob 的参数，恢复时解码后交给 RegisterTypedFunc 注册的函数
+	Payload json.RawMessage `json:"payload,omitempty"`
+	// LastRun 最近一次开始执行的时间，Stop 时更新
+	LastRun time.Time `json:"last_run,omitempty"`
+}
+
+// JobStore 持久化通过 AddStoredJob 添加的任务，进程重启后在 Start 时重新注册
+type JobStore interface {
+	// Save 保存任务，已有同名记录时覆盖
+	Save(rec JobRecord) error
+	// Load 读取所有任务
+	Load() ([]JobRecord, error)
+	// Delete 删除任务，记录不存在时不返回错误
+	Delete(name string) error
+}
+
+type _JobStore struct {
+	JobStore
+}
+
+func (st _JobStore) applyCron(opts *cronOptions) {
+	opts.JobStore = st.JobStore
+}
+
+// WithJobStore 设置任务的持久化存储，第一次 Start 时从中恢复任务，见 Restore
+func WithJobStore(st JobStore) CronOption {
+	return _JobStore{st}
+}
+
+type _Func string
+
+func (f _Func) apply(opts *options) {
+	opts.Func = string(f)
+}
+
+// RegisterFunc 注册任务函数，AddStoredJob 和 Restore 按 name 查找
+// 需要在 Start 之前注册，否则恢复时找不到函数；重复注册会覆盖之前的函数
+func (s *Cron) RegisterFunc(name string, f func()) {
+	s.lock.Lock()
+	s.funcs[name] = f
+	s.lock.Unlock()
+}
+
+// lookupFunc 返回注册的任务函数
+func (s *Cron) lookupFunc(name string) (func(), bool) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	f, ok := s.funcs[name]
+	return f, ok
+}
+
+// AddStoredJob 添加任务并保存到 JobStore，fn 为 RegisterFunc 注册的函数名
+// 任务名同时作为记录的 key，之后 ReloadJob、RemoveJob 等操作会同步到 JobStore，
+// ReloadJob 没有指定 WithName 时保留原来的任务名，指定了新名字时删除原来的记录；
+// 只有 Group、RunMode 和 Timeout 会被保存，其他配置在恢复后使用默认值
+// name 为空返回 ErrNoName，fn 未注册返回 ErrUnknownFunc，未设置 WithJobStore 时与 AddNamedJob 相同
+func (s *Cron) AddStoredJob(name, spec, fn string, options ...Option) (id int, err error) {
+	if name == "" {
+		return -1, ErrNoName
+	}
+	f, ok := s.lookupFunc(fn)
+	if !ok {
+		return -1, fmt.Errorf("%w: %s", ErrUnknownFunc, fn)
+	}
+	id, err = s.AddJobE(spec, f, append(options, WithName(name), _Func(fn))...)
+	if err != nil {
+		return -1, err
+	}
+	if err = s.persistJob(id); err != nil {
+		s.RemoveJob(id)
+		return -1, err
+	}
+	return id, nil
+}
+
+// Restore 从 JobStore 重新注册保存的任务，第一次 Start 时会自动调用
+// 已有同名任务时替换它；函数未注册或 spec 无效的记录会被跳过，返回遇到的第一个错误
+func (s *Cron) Restore() error {
+	if s.jobs == nil {
+		return nil
+	}
+	recs, err := s.jobs.Load()
+	if err != nil {
+		return err
+	}
+
+	var first error
+	for _, rec := range recs {
+		if err := s.restore(rec); err != nil {
+			s.logger.Error(err, "restore job failed", "name", rec.Name, "spec", rec.Spec)
+			if first == nil {
+				first = err
+			}
+		}
+	}
+	return first
+}
+
+// restore 重新注册一条记录
+func (s *Cron) restore(rec JobRecord) error {
+	options := []Option{WithName(rec.Name), WithGroup(rec.Group), WithRunMode(rec.RunMode), WithTimeout(rec.Timeout)}
+	var (
+		id  int
+		err error
+	)
+	if len(rec.Payload) > 0 {
+		id, err = s.addTyped(rec.Spec, rec.Func, rec.Payload, options)
+	} else {
+		f, ok := s.lookupFunc(rec.Func)
+		if !ok {
+			return fmt.Errorf("%w: %s", ErrUnknownFunc, rec.Func)
+		}
+		id, err = s.AddJobE(rec.Spec, f, append(options, _Func(rec.Func))...)
+	}
+	if err != nil {
+		return err
+	}
+	if e, ok := s.load(id); ok && !rec.LastRun.IsZero() {
+		atomic.CompareAndSwapInt64(&e.counters.lastRun, 0, rec.LastRun.UnixNano())
+	}
+	return nil
+}
+
+// record 生成任务的持久化记录，调用方需持有读锁
+func (e *entry) record() JobRecord {
+	rec := JobRecord{
+		Name:    e.opt.Name,
+		Func:    e.opt.Func,
+		Group:   e.opt.Group,
+		RunMode: e.opt.RunMode,
+		Timeout: e.opt.Timeout,
+		Payload: e.opt.PayloadJSON,
+	}
+	if len(e.specs) > 0 {
+		rec.Spec = e.specs[0]
+	}
+	if n := atomic.LoadInt64(&e.counters.lastRun); n != 0 {
+		rec.LastRun = time.Unix(0, n)
+	}
+	return rec
+}
+
+// persistJob 将 AddStoredJob 添加的任务保存到 JobStore，其他任务什么也不做
+func (s *Cron) persistJob(id int) error {
+	if s.jobs == nil {
+		return nil
+	}
+	s.lock.RLock()
+	e, ok := s.load(id)
+	if !ok || e.opt.Func == "" {
+		s.lock.RUnlock()
+		return nil
+	}
+	rec := e.record()
+	s.lock.RUnlock()
+	return s.jobs.Save(rec)
+}
+
+// storedName 返回 AddStoredJob 添加的任务的任务名，其他任务或未设置 WithJobStore 时返回空字符串
+func (s *Cron) storedName(id int) string {
+	if s.jobs == nil {
+		return ""
+	}
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	e, ok := s.load(id)
+	if !ok || e.opt.Func == "" {
+		return ""
+	}
+	return e.opt.Name
+}
+
+// unpersistJob 从 JobStore 删除任务
+func (s *Cron) unpersistJob(name string) {
+	if err := s.jobs.Delete(name); err != nil {
+		s.logger.Error(err, "delete stored job failed", "name", name)
+	}
+}
+
+// FileStore 将任务以 JSON 保存在单个文件中的 JobStore
+// 每次修改都会重写整个文件，适合任务数量不多的场景
+type FileStore struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewFileStore 创建使用 path 保存任务的 FileStore，文件不存在时会在第一次保存时创建
+func NewFileStore(path string) *FileStore {
+	return &FileStore{path: path}
+}
+
+func (f *FileStore) Save(rec JobRecord) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	recs, err := f.read()
+	if err != nil {
+		return err
+	}
+	recs[rec.Name] = rec
+	return f.write(recs)
+}
+
+func (f *FileStore) Load() ([]JobRecord, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	recs, err := f.read()
+	if err != nil {
+		return nil, err
+	}
+	out := make([]JobRecord, 0, len(recs))
+	for _, rec := range recs {
+		out = append(out, rec)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].Name < out[j].Name
+	})
+	return out, nil
+}
+
+func (f *FileStore) Delete(name string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	recs, err := f.read()
+	if err != nil {
+		return err
+	}
+	if _, ok := recs[name]; !ok {
+		return nil
+	}
+	delete(recs, name)
+	return f.write(recs)
+}
+
+// read 读取所有记录，文件不存在时返回空集合
+func (f *FileStore) read() (map[string]JobRecord, error) {
+	recs := make(map[string]JobRecord)
+	data, err := os.ReadFile(f.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return recs, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return recs, nil
+	}
+	if err := json.Unmarshal(data, &recs); err != nil {
+		return nil, fmt.Errorf("cron: decode %s: %w", f.path, err)
+	}
+	return recs, nil
+}
+
+// write 先写临时文件再重命名，避免写到一半时进程退出留下损坏的文件
+func (f *FileStore) write(recs map[string]JobRecord) error {
+	data, err := json.MarshalIndent(recs, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(f.path), filepath.Base(f.path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), f.path)
+}
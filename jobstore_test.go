@@ -0,0 +1,217 @@
+package cron
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestFileStoreRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "jobs.json")
+	st := NewFileStore(path)
+	if recs, err := st.Load(); err != nil || len(recs) != 0 {
+		t.Fatalf("missing file: %v, %v", recs, err)
+	}
+
+	a := JobRecord{Name: "a", Func: "report", Spec: "0 0 9 * * *", Group: "daily", RunMode: ModeJobParallel, Timeout: time.Minute}
+	b := JobRecord{Name: "b", Func: "cleanup", Spec: "@every 1h", LastRun: time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)}
+	for _, rec := range []JobRecord{b, a} {
+		if err := st.Save(rec); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// 其他实例读取同一个文件
+	recs, err := NewFileStore(path).Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recs) != 2 || !reflect.DeepEqual(recs[0], a) || !recs[1].LastRun.Equal(b.LastRun) || recs[1].Spec != b.Spec {
+		t.Fatalf("loaded %+v", recs)
+	}
+
+	a.Spec = "0 30 10 * * *"
+	if err := st.Save(a); err != nil {
+		t.Fatal(err)
+	}
+	if err := st.Delete("b"); err != nil {
+		t.Fatal(err)
+	}
+	if err := st.Delete("missing"); err != nil {
+		t.Errorf("deleting a missing record: %v", err)
+	}
+	recs, _ = st.Load()
+	if len(recs) != 1 || recs[0].Spec != "0 30 10 * * *" {
+		t.Errorf("after update and delete: %+v", recs)
+	}
+	if tmps, _ := filepath.Glob(path + ".tmp*"); len(tmps) != 0 {
+		t.Errorf("temporary files left behind: %v", tmps)
+	}
+}
+
+func TestFileStoreCorruptFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "jobs.json")
+	if err := os.WriteFile(path, []byte("{not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	st := NewFileStore(path)
+	if _, err := st.Load(); err == nil {
+		t.Error("Load accepted a corrupt file")
+	}
+	// 损坏的文件不会被覆盖，避免丢失其中的记录
+	if err := st.Save(JobRecord{Name: "a"}); err == nil {
+		t.Error("Save overwrote a corrupt file")
+	}
+	if data, _ := os.ReadFile(path); string(data) != "{not json" {
+		t.Errorf("file rewritten to %q", data)
+	}
+
+	c := NewCron(WithJobStore(st), WithLogger(DiscardLogger))
+	if err := c.Restore(); err == nil {
+		t.Error("Restore ignored a corrupt file")
+	}
+
+	empty := filepath.Join(t.TempDir(), "empty.json")
+	if err := os.WriteFile(empty, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if recs, err := NewFileStore(empty).Load(); err != nil || len(recs) != 0 {
+		t.Errorf("empty file: %v, %v", recs, err)
+	}
+}
+
+func TestStoredJobsSurviveRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "jobs.json")
+	c := NewCron(WithJobStore(NewFileStore(path)), WithLogger(DiscardLogger))
+	c.RegisterFunc("report", func() {})
+	if _, err := c.AddStoredJob("daily", "0 0 9 * * *", "report", WithGroup("reports"), WithTimeout(time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	weekly, err := c.AddStoredJob("weekly", "0 0 9 * * 1", "report")
+	if err != nil {
+		t.Fatal(err)
+	}
+	gone, err := c.AddStoredJob("gone", "0 0 9 * * *", "report")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.ReloadJob(weekly, "0 0 10 * * 1", WithRunMode(ModeJobParallel)); err != nil {
+		t.Fatal(err)
+	}
+	c.RemoveJob(gone)
+	// 没有通过 AddStoredJob 添加的任务不会保存
+	c.AddJob("0 0 9 * * *", func() {}, WithName("transient"))
+
+	// 重启后从文件恢复
+	ran := make(chan struct{}, 1)
+	again := NewCron(WithJobStore(NewFileStore(path)), WithLogger(DiscardLogger))
+	again.RegisterFunc("report", func() { ran <- struct{}{} })
+	again.Start()
+	defer again.Stop()
+
+	jobs := again.ListJobs()
+	if len(jobs) != 2 {
+		t.Fatalf("restored %+v, want daily and weekly", jobs)
+	}
+	daily, ok := again.GetJobByName("daily")
+	if !ok || daily.Spec != "0 0 9 * * *" || daily.Group != "reports" {
+		t.Errorf("daily restored as %+v", daily)
+	}
+	if _, opt, _ := again.loadOptions(daily.ID); opt.Timeout != time.Minute {
+		t.Errorf("daily timeout = %v", opt.Timeout)
+	}
+	w, ok := again.GetJobByName("weekly")
+	if !ok || w.Spec != "0 0 10 * * 1" {
+		t.Errorf("weekly restored as %+v", w)
+	}
+	if _, opt, _ := again.loadOptions(w.ID); opt.RunMode != ModeJobParallel {
+		t.Errorf("weekly run mode = %v", opt.RunMode)
+	}
+	again.CallByName("daily")
+	receive(t, ran)
+}
+
+func TestRestoreSkipsUnknownFunc(t *testing.T) {
+	st := NewFileStore(filepath.Join(t.TempDir(), "jobs.json"))
+	for _, rec := range []JobRecord{
+		{Name: "a", Func: "known", Spec: "0 0 9 * * *"},
+		{Name: "b", Func: "unknown", Spec: "0 0 9 * * *"},
+		{Name: "c", Func: "known", Spec: "bogus"},
+	} {
+		if err := st.Save(rec); err != nil {
+			t.Fatal(err)
+		}
+	}
+	c := NewCron(WithJobStore(st), WithLogger(DiscardLogger))
+	c.RegisterFunc("known", func() {})
+	if err := c.Restore(); !errors.Is(err, ErrUnknownFunc) {
+		t.Errorf("Restore() = %v, want the first error ErrUnknownFunc", err)
+	}
+	if _, ok := c.GetJobByName("a"); !ok || c.Count() != 1 {
+		t.Errorf("restored %+v, want only a", c.ListJobs())
+	}
+}
+
+func TestStoredTypedJobSurvivesRestart(t *testing.T) {
+	type report struct{ Region string }
+	path := filepath.Join(t.TempDir(), "jobs.json")
+	c := NewCron(WithJobStore(NewFileStore(path)), WithLogger(DiscardLogger))
+	RegisterTypedFunc(c, "report", func(context.Context, report) error { return nil })
+	if _, err := AddStoredTypedJob(c, "eu", "0 0 9 * * *", "report", report{Region: "eu"}); err != nil {
+		t.Fatal(err)
+	}
+
+	got := make(chan string, 1)
+	again := NewCron(WithJobStore(NewFileStore(path)), WithLogger(DiscardLogger))
+	RegisterTypedFunc(again, "report", func(_ context.Context, r report) error {
+		got <- r.Region
+		return nil
+	})
+	if err := again.Restore(); err != nil {
+		t.Fatal(err)
+	}
+	again.CallByName("eu")
+	if region := receive(t, got); region != "eu" {
+		t.Errorf("payload restored as %q", region)
+	}
+}
+
+func TestStoredJobRequiresName(t *testing.T) {
+	st := NewFileStore(filepath.Join(t.TempDir(), "jobs.json"))
+	c := NewCron(WithJobStore(st), WithLogger(DiscardLogger))
+	c.RegisterFunc("report", func() {})
+	RegisterTypedFunc(c, "typed", func(context.Context, int) error { return nil })
+
+	if id, err := c.AddStoredJob("", "0 0 9 * * *", "report"); id != -1 || !errors.Is(err, ErrNoName) {
+		t.Errorf("AddStoredJob: id %d, err %v", id, err)
+	}
+	if id, err := AddStoredTypedJob(c, "", "0 0 9 * * *", "typed", 1); id != -1 || !errors.Is(err, ErrNoName) {
+		t.Errorf("AddStoredTypedJob: id %d, err %v", id, err)
+	}
+	if recs, _ := st.Load(); len(recs) != 0 {
+		t.Errorf("saved %+v", recs)
+	}
+	if n := c.Count(); n != 0 {
+		t.Errorf("%d jobs registered", n)
+	}
+}
+
+func TestReloadStoredJobRename(t *testing.T) {
+	st := NewFileStore(filepath.Join(t.TempDir(), "jobs.json"))
+	c := NewCron(WithJobStore(st), WithLogger(DiscardLogger))
+	c.RegisterFunc("report", func() {})
+	id, err := c.AddStoredJob("daily", "0 0 9 * * *", "report")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.ReloadJob(id, "0 0 10 * * *", WithName("morning")); err != nil {
+		t.Fatal(err)
+	}
+	recs, _ := st.Load()
+	if len(recs) != 1 || recs[0].Name != "morning" || recs[0].Spec != "0 0 10 * * *" {
+		t.Errorf("records after rename: %+v", recs)
+	}
+}
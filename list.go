@@ -0,0 +1,90 @@
+package cron
+
+import (
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// JobInfo 任务的概要信息
+type JobInfo struct {
+	// ID 任务 ID
+	ID int
+	// Status 任务状态
+	Status uint
+	// Spec 注册时的 spec，分组任务的多个 spec 以 ";" 连接
+	Spec string
+	// Next 下一次触发时间，见 NextRun
+	Next time.Time
+	// Prev 上一次定时触发的时间，见 PrevRun
+	Prev time.Time
+	// Name 任务名，见 WithName
+	Name string
+	// Group 任务所属的分组，见 WithGroup
+	Group string
+	// LastRun 最近一次开始执行的时间，包括 Call 和立即执行，从未执行为零值
+	LastRun time.Time
+	// LastDuration 最近一次执行的耗时
+	LastDuration time.Duration
+	// LastError 最近一次执行的错误
+	LastError error
+	// Runs 开始执行的次数
+	Runs uint64
+	// Payload 任务携带的参数，见 AddTypedJob
+	Payload interface{}
+}
+
+// ListJobs 返回所有任务的概要信息，按 id 排序
+func (s *Cron) ListJobs() []JobInfo {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	var out []JobInfo
+	s.entry.Range(func(key, value interface{}) bool {
+		out = append(out, value.(*entry).info(s))
+		return true
+	})
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].ID < out[j].ID
+	})
+	return out
+}
+
+// jobInfo 返回单个任务的概要信息
+func (s *Cron) jobInfo(id int) (JobInfo, bool) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	e, ok := s.load(id)
+	if !ok {
+		return JobInfo{}, false
+	}
+	return e.info(s), true
+}
+
+// info 生成概要信息，调用方需持有读锁
+func (e *entry) info(s *Cron) JobInfo {
+	return JobInfo{
+		ID:           e.id,
+		Status:       e.getStatus(),
+		Spec:         strings.Join(e.specs, ";"),
+		Next:         e.next(s),
+		Prev:         e.prev(s),
+		Name:         e.opt.Name,
+		Group:        e.opt.Group,
+		LastRun:      unixNano(atomic.LoadInt64(&e.counters.lastRun)),
+		LastDuration: time.Duration(atomic.LoadInt64(&e.counters.lastDuration)),
+		LastError:    e.result.get(),
+		Runs:         atomic.LoadUint64(&e.counters.runs),
+		Payload:      e.opt.Payload,
+	}
+}
+
+// Count 返回当前注册的任务数量
+func (s *Cron) Count() int {
+	n := 0
+	s.entry.Range(func(_, _ interface{}) bool {
+		n++
+		return true
+	})
+	return n
+}
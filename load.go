@@ -0,0 +1,69 @@
+package cron
+
+import (
+	"sync"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+const (
+	// loadCacheTTL EstimatedLoad 结果的缓存时间
+	loadCacheTTL = time.Second
+	// maxLoadFires 单个调度最多统计的触发次数，防止窗口过大时计算过久
+	maxLoadFires = 1 << 20
+)
+
+// loadCache 缓存最近一次 EstimatedLoad 的结果
+type loadCache struct {
+	mu     sync.Mutex
+	at     time.Time
+	window time.Duration
+	value  float64
+}
+
+// EstimatedLoad 预估从现在开始 window 时间内所有任务的触发次数
+// 根据每个调度依次计算 Next 得出，不执行任何任务，可用于评估并发资源；与 DryRun 一样不计入暂停的任务，
+// 使用调度器的时钟，见 WithClock
+// 结果会缓存 loadCacheTTL，期间以相同 window 调用直接返回缓存
+func (s *Cron) EstimatedLoad(window time.Duration) float64 {
+	s.loadCache.mu.Lock()
+	defer s.loadCache.mu.Unlock()
+
+	now := s.now()
+	if s.loadCache.window == window && now.Sub(s.loadCache.at) < loadCacheTTL {
+		return s.loadCache.value
+	}
+
+	var (
+		end   = now.Add(window)
+		total float64
+	)
+	s.lock.RLock()
+	s.entry.Range(func(_, value interface{}) bool {
+		e := value.(*entry)
+		if e.paused {
+			return true
+		}
+		for _, sched := range e.scheds {
+			total += float64(countFires(sched, now, end))
+		}
+		return true
+	})
+	s.lock.RUnlock()
+
+	s.loadCache.at, s.loadCache.window, s.loadCache.value = now, window, total
+	return total
+}
+
+// countFires 统计调度在 (from, to] 内的触发次数
+func countFires(sched cron.Schedule, from, to time.Time) int {
+	n := 0
+	for t := from; n < maxLoadFires; n++ {
+		t = peekNext(sched, t)
+		if t.IsZero() || t.After(to) {
+			break
+		}
+	}
+	return n
+}
@@ -0,0 +1,42 @@
+package cron
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEstimatedLoadMatchesDryRun(t *testing.T) {
+	c, clk := newFakeCron(t)
+	c.AddJob("*/10 * * * * *", func() {})
+	c.AddJob("0 * * * * *", func() {})
+	paused := c.AddJob("* * * * * *", func() {})
+	c.PauseJob(paused)
+
+	// testStart 为 08:59:55，一分钟内 */10 触发 6 次，整分触发 1 次
+	if got := c.EstimatedLoad(time.Minute); got != 7 {
+		t.Errorf("EstimatedLoad = %v, want 7", got)
+	}
+	if n := len(c.DryRun(clk.Now(), clk.Now().Add(time.Minute))); n != 7 {
+		t.Errorf("DryRun returned %d firings, want 7", n)
+	}
+
+	// 推进虚拟时间之后按新的时间计算，而不是系统时间
+	clk.Advance(10 * time.Minute)
+	c.ResumeJob(paused)
+	if got, want := c.EstimatedLoad(time.Minute), float64(len(c.DryRun(clk.Now(), clk.Now().Add(time.Minute)))); got != want {
+		t.Errorf("EstimatedLoad = %v, DryRun = %v", got, want)
+	}
+}
+
+func TestEstimatedLoadCached(t *testing.T) {
+	c, _ := newFakeCron(t)
+	c.AddJob("* * * * * *", func() {})
+	first := c.EstimatedLoad(time.Minute)
+	c.AddJob("* * * * * *", func() {})
+	if got := c.EstimatedLoad(time.Minute); got != first {
+		t.Errorf("cached EstimatedLoad = %v, want %v", got, first)
+	}
+	if got := c.EstimatedLoad(2 * time.Minute); got != 240 {
+		t.Errorf("EstimatedLoad(2m) = %v, want 240", got)
+	}
+}
@@ -0,0 +1,62 @@
+package cron
+
+import (
+	"strings"
+	"time"
+)
+
+type _Location struct {
+	loc *time.Location
+}
+
+func (l _Location) applyCron(opts *cronOptions) {
+	opts.Location = l.loc
+}
+
+// WithLocation 设置解析 spec 使用的时区，默认使用本地时区
+// AddHourJob 等辅助方法生成的 spec 同样按该时区理解，比如随机选择的小时在该时区的一天之内
+func WithLocation(loc *time.Location) CronOption {
+	return _Location{loc}
+}
+
+type _Timezone struct {
+	loc *time.Location
+}
+
+func (l _Timezone) apply(opts *options) {
+	opts.Timezone = l.loc
+}
+
+// WithTimezone 让任务的 spec 按 loc 理解，不受调度器 WithLocation 的影响，
+// 效果与在 spec 前加上 "CRON_TZ=" + loc.String() 相同，spec 自带时区时以 spec 为准
+// loc 需要能被 time.LoadLocation 按名字重新加载，比如 time.LoadLocation("Asia/Shanghai") 的结果，
+// time.FixedZone 创建的时区无法使用；对 AddJob 和 AddHourJob 等辅助方法都生效
+func WithTimezone(loc *time.Location) Option {
+	return _Timezone{loc}
+}
+
+// withTimezone 为没有指定时区的 spec 加上 loc
+func withTimezone(spec string, loc *time.Location) string {
+	if loc == nil {
+		return spec
+	}
+	if tz, _ := splitTZ(spec); tz != "" {
+		return spec
+	}
+	return "CRON_TZ=" + loc.String() + " " + spec
+}
+
+// tzName 返回 TZ= 或 CRON_TZ= 中的时区名
+func tzName(tz string) string {
+	return tz[strings.Index(tz, "=")+1:]
+}
+
+// NewCronWithLocation 等同于 NewCron(WithLocation(loc), options...)
+func NewCronWithLocation(loc *time.Location, options ...CronOption) *Cron {
+	return NewCron(append([]CronOption{WithLocation(loc)}, options...)...)
+}
+
+// now 返回调度器时区下的当前时间
+func (s *Cron) now() time.Time {
+	return s.clock.Now().In(s.location)
+}
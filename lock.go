@@ -0,0 +1,94 @@
+package cron
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Locker 分布式锁，用于多个实例部署时同一任务只在一个实例上执行
+// 本包不依赖具体存储，Redis 可以使用 RedisLocker，etcd 等由使用方实现
+type Locker interface {
+	// TryLock 尝试获取锁，锁已被持有时返回 false 和 nil
+	TryLock(ctx context.Context, key string, ttl time.Duration) (bool, error)
+	// Unlock 释放锁
+	Unlock(ctx context.Context, key string) error
+}
+
+type _Locker struct {
+	Locker
+}
+
+func (l _Locker) apply(opts *options) {
+	opts.Locker = l.Locker
+}
+
+// WithDistributedLock 每次执行前获取分布式锁，获取不到时跳过本次执行，reason 为 SkipReasonLock
+// 锁的 key 为 "cron:" 加任务名，未设置 WithName 时使用 id，多实例部署时建议设置任务名，
+// 否则各实例注册顺序不同会导致 id 不一致
+// 获取或释放锁出错时交给 WithErrorHandler，attempt 为 0
+func WithDistributedLock(locker Locker) Option {
+	return _Locker{locker}
+}
+
+type _LockTTL time.Duration
+
+func (d _LockTTL) apply(opts *options) {
+	opts.LockTTL = time.Duration(d)
+}
+
+// WithLockTTL 设置分布式锁的过期时间，应当大于任务的最长执行时间
+func WithLockTTL(ttl time.Duration) Option {
+	return _LockTTL(ttl)
+}
+
+// lockKey 任务的分布式锁 key
+func lockKey(id int, opt options) string {
+	if opt.Name != "" {
+		return "cron:" + opt.Name
+	}
+	return fmt.Sprintf("cron:%d", id)
+}
+
+// lockRunKey ctx 中本次执行的标识，TryLock 和 Unlock 收到同一个值，见 lockRunOf
+type lockRunKey struct{}
+
+// lockRun 一次执行的标识，只比较地址
+type lockRun struct{ _ byte }
+
+// lockRunOf 返回 ctx 中的执行标识，不是由调度器传入的 ctx 返回 nil
+func lockRunOf(ctx context.Context) *lockRun {
+	run, _ := ctx.Value(lockRunKey{}).(*lockRun)
+	return run
+}
+
+// distributed 包装任务函数，持有分布式锁时才执行
+// 每次执行的 TryLock 和 Unlock 携带同一个执行标识，同一进程内重叠的执行不会释放彼此的锁
+func (s *Cron) distributed(id int, f execFunc, opt options) execFunc {
+	key := lockKey(id, opt)
+	return func(t trigger) {
+		s.lock.RLock()
+		root := s.root
+		s.lock.RUnlock()
+
+		run := &lockRun{}
+		ok, err := opt.Locker.TryLock(context.WithValue(root, lockRunKey{}, run), key, opt.LockTTL)
+		if err != nil {
+			s.unclaim(id, t)
+			s.fail(id, opt, 0, err)
+			return
+		}
+		if !ok {
+			s.unclaim(id, t)
+			s.skip(id, SkipReasonLock, time.Now())
+			return
+		}
+		defer func() {
+			// Stop 之后 root 已经取消，释放锁不能使用它
+			if err := opt.Locker.Unlock(context.WithValue(context.Background(), lockRunKey{}, run), key); err != nil {
+				s.fail(id, opt, 0, err)
+			}
+		}()
+		f(t)
+	}
+}
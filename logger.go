@@ -0,0 +1,64 @@
+package cron
+
+import (
+	"strings"
+
+	"github.com/robfig/cron/v3"
+)
+
+// Logger 日志接口，与 robfig/cron 的 cron.Logger 相同，
+// 可以直接使用 cron.PrintfLogger、cron.VerbosePrintfLogger 或其他兼容的实现
+// keysAndValues 为交替出现的键和值，任务相关的日志会带上 "id"、"name" 和 "spec"
+type Logger interface {
+	// Info 记录任务的添加、删除、跳过等事件
+	Info(msg string, keysAndValues ...interface{})
+	// Error 记录 panic、执行失败、超时等错误
+	Error(err error, msg string, keysAndValues ...interface{})
+}
+
+// DefaultLogger 默认日志，Error 打印到标准输出，Info 被丢弃
+var DefaultLogger Logger = cron.DefaultLogger
+
+// DiscardLogger 丢弃所有日志
+var DiscardLogger Logger = cron.DiscardLogger
+
+type _Logger struct {
+	Logger
+}
+
+func (l _Logger) apply(opts *options) {
+	opts.Logger = l.Logger
+}
+
+func (l _Logger) applyCron(opts *cronOptions) {
+	opts.Logger = l.Logger
+}
+
+// WithLogger 设置日志，传给 NewCron 时作为所有任务的默认值，添加任务时传入只对该任务生效
+func WithLogger(l Logger) CommonOption {
+	return _Logger{l}
+}
+
+// jobLogger 返回任务使用的日志和描述任务的字段，调用方不能持有锁
+func (s *Cron) jobLogger(id int) (Logger, []interface{}) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	e, ok := s.load(id)
+	if !ok {
+		return s.logger, []interface{}{"id", id}
+	}
+	return e.logger(s)
+}
+
+// logger 返回任务使用的日志和描述任务的字段，调用方需持有读锁
+func (e *entry) logger(s *Cron) (Logger, []interface{}) {
+	kv := []interface{}{"id", e.id}
+	if e.opt.Name != "" {
+		kv = append(kv, "name", e.opt.Name)
+	}
+	kv = append(kv, "spec", strings.Join(e.specs, ";"))
+	if e.opt.Logger != nil {
+		return e.opt.Logger, kv
+	}
+	return s.logger, kv
+}
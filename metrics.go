@@ -0,0 +1,51 @@
+package cron
+
+import (
+	"errors"
+	"time"
+)
+
+// Metrics 接收任务执行的指标，可以用来对接 Prometheus 等监控系统，
+// 比如 JobStarted 时增加正在执行的 gauge，JobFinished 时减少它并记录耗时的 histogram
+// name 为 WithName 设置的任务名，没有设置时为空字符串；实现需要保证并发安全
+type Metrics interface {
+	// JobStarted 每次执行开始时调用
+	JobStarted(id int, name string)
+	// JobFinished 每次执行结束时调用，err 为任务返回的错误，panicked 表示执行中发生了 panic
+	JobFinished(id int, name string, d time.Duration, err error, panicked bool)
+	// JobSkipped 触发被跳过时调用，reason 为 SkipReason* 常量之一
+	JobSkipped(id int, name string, reason string)
+}
+
+type _Metrics struct {
+	Metrics
+}
+
+func (m _Metrics) apply(opts *options) {
+	opts.Metrics = append(opts.Metrics, m.Metrics)
+}
+
+func (m _Metrics) applyCron(opts *cronOptions) {
+	opts.Metrics = append(opts.Metrics, m.Metrics)
+}
+
+// WithMetrics 上报执行指标，传给 NewCron 时作用于所有任务，添加任务时传入只作用于该任务
+func WithMetrics(m Metrics) CommonOption {
+	return _Metrics{m}
+}
+
+// metricsHooks 将 Metrics 转换为生命周期回调
+func metricsHooks(m Metrics, name string) Hooks {
+	return Hooks{
+		OnStart: func(id int) {
+			m.JobStarted(id, name)
+		},
+		OnComplete: func(id int, d time.Duration, err error) {
+			var p *PanicError
+			m.JobFinished(id, name, d, err, errors.As(err, &p))
+		},
+		OnSkip: func(id int, reason string) {
+			m.JobSkipped(id, name, reason)
+		},
+	}
+}
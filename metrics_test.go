@@ -0,0 +1,95 @@
+package cron
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// metricsRecorder 以字符串记录 Metrics 的每次调用
+type metricsRecorder struct {
+	mu     sync.Mutex
+	events []string
+	durs   []time.Duration
+}
+
+func (m *metricsRecorder) add(event string) {
+	m.mu.Lock()
+	m.events = append(m.events, event)
+	m.mu.Unlock()
+}
+
+func (m *metricsRecorder) JobStarted(id int, name string) {
+	m.add(fmt.Sprintf("start %d %s", id, name))
+}
+
+func (m *metricsRecorder) JobFinished(id int, name string, d time.Duration, err error, panicked bool) {
+	m.mu.Lock()
+	m.durs = append(m.durs, d)
+	m.mu.Unlock()
+	m.add(fmt.Sprintf("finish %d %s %v %v", id, name, err, panicked))
+}
+
+func (m *metricsRecorder) JobSkipped(id int, name string, reason string) {
+	m.add(fmt.Sprintf("skip %d %s %s", id, name, reason))
+}
+
+func (m *metricsRecorder) get() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]string(nil), m.events...)
+}
+
+func TestMetrics(t *testing.T) {
+	m := &metricsRecorder{}
+	c := NewCron(WithMetrics(m), WithLogger(DiscardLogger))
+	ok := c.AddJob("0 0 9 * * *", func() { time.Sleep(5 * time.Millisecond) }, WithName("report"))
+	failing := c.AddJobE2("0 0 9 * * *", func() error { return errors.New("boom") })
+	panicking := c.AddJob("0 0 9 * * *", func() { panic("boom") }, WithRateLimit(rate.Every(time.Hour), 1))
+	c.Call(ok)
+	c.Call(failing)
+	c.Call(panicking)
+	c.Call(panicking)
+
+	want := []string{
+		fmt.Sprintf("start %d report", ok),
+		fmt.Sprintf("finish %d report <nil> false", ok),
+		fmt.Sprintf("start %d ", failing),
+		fmt.Sprintf("finish %d  boom false", failing),
+		fmt.Sprintf("start %d ", panicking),
+		fmt.Sprintf("finish %d  panic: boom true", panicking),
+		fmt.Sprintf("skip %d  %s", panicking, SkipReasonRateLimit),
+	}
+	got := m.get()
+	if len(got) != len(want) {
+		t.Fatalf("events = %q, want %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("event %d = %q, want %q", i, got[i], want[i])
+		}
+	}
+	if m.durs[0] < 5*time.Millisecond {
+		t.Errorf("duration = %v, want at least 5ms", m.durs[0])
+	}
+}
+
+func TestMetricsPerJob(t *testing.T) {
+	global, local := &metricsRecorder{}, &metricsRecorder{}
+	c := NewCron(WithMetrics(global), WithLogger(DiscardLogger))
+	a := c.AddJob("0 0 9 * * *", func() {}, WithMetrics(local))
+	b := c.AddJob("0 0 9 * * *", func() {})
+	c.Call(a)
+	c.Call(b)
+	// 添加任务时传入的只作用于该任务，NewCron 传入的作用于所有任务
+	if got := local.get(); len(got) != 2 || got[0] != fmt.Sprintf("start %d ", a) {
+		t.Errorf("job metrics = %q", got)
+	}
+	if n := len(global.get()); n != 4 {
+		t.Errorf("global metrics got %d events, want 4", n)
+	}
+}
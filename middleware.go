@@ -0,0 +1,80 @@
+package cron
+
+import "time"
+
+// JobMiddleware 包装任务的每次执行，next 为被包装的执行，返回包装后的执行
+// 每次执行都会调用一次，需要跨执行保存的状态应放在 JobMiddleware 之外
+// 只有真正开始的执行才会经过中间件，被执行策略、幂等键或分布式锁跳过的触发不会
+type JobMiddleware func(next func()) func()
+
+type _Middleware []JobMiddleware
+
+func (m _Middleware) apply(opts *options) {
+	opts.Middlewares = append(opts.Middlewares, m...)
+}
+
+func (m _Middleware) applyCron(opts *cronOptions) {
+	opts.Middlewares = append(opts.Middlewares, m...)
+}
+
+// WithMiddleware 添加中间件，靠前的在外层
+// 传给 NewCron 时作用于所有任务，并且位于任务自己的中间件外层
+func WithMiddleware(mw ...JobMiddleware) CommonOption {
+	return _Middleware(mw)
+}
+
+// Hooks 任务生命周期的回调，不需要的字段留空即可
+type Hooks struct {
+	// OnStart 每次执行开始时调用
+	OnStart func(id int)
+	// OnComplete 每次执行结束时调用，err 为任务返回的错误，panic 或超时时同样不为 nil
+	OnComplete func(id int, d time.Duration, err error)
+	// OnPanic 执行中发生 panic 时调用，在 OnComplete 之后、PanicHandler 之前
+	OnPanic func(id int, recovered interface{})
+	// OnSkip 触发被跳过时调用，reason 为 SkipReason* 常量之一
+	OnSkip func(id int, reason string)
+}
+
+type _Hooks Hooks
+
+func (h _Hooks) apply(opts *options) {
+	opts.Hooks = append(opts.Hooks, Hooks(h))
+}
+
+func (h _Hooks) applyCron(opts *cronOptions) {
+	opts.Hooks = append(opts.Hooks, Hooks(h))
+}
+
+// WithHooks 注册生命周期回调，可以多次使用，按注册顺序调用
+// 传给 NewCron 时作用于所有任务，并且先于任务自己的回调调用
+func WithHooks(h Hooks) CommonOption {
+	return _Hooks(h)
+}
+
+// middleware 按调度器和任务的中间件包装 f，每次执行重新包装，中间件拿到的 next 只对应这一次执行
+func (s *Cron) middleware(f execFunc, opt options) execFunc {
+	mws := append(append([]JobMiddleware(nil), s.middlewares...), opt.Middlewares...)
+	return func(t trigger) {
+		run := func() { f(t) }
+		for i := len(mws) - 1; i >= 0; i-- {
+			run = mws[i](run)
+		}
+		run()
+	}
+}
+
+// eachHooks 依次对调度器和任务的回调以及 Metrics 调用 f
+func (s *Cron) eachHooks(opt options, f func(h Hooks)) {
+	for _, h := range s.lifecycle {
+		f(h)
+	}
+	for _, h := range opt.Hooks {
+		f(h)
+	}
+	for _, m := range s.metrics {
+		f(metricsHooks(m, opt.Name))
+	}
+	for _, m := range opt.Metrics {
+		f(metricsHooks(m, opt.Name))
+	}
+}
@@ -0,0 +1,68 @@
+package cron
+
+// MutexPolicy 互斥组被占用时的处理方式，见 WithMutexGroup
+type MutexPolicy int
+
+const (
+	// MutexSkip 跳过本次执行，reason 为 SkipReasonMutex
+	MutexSkip MutexPolicy = iota
+	// MutexWait 等待组内其他任务执行结束，调度器 Stop 时放弃等待
+	MutexWait
+)
+
+type _MutexGroup struct {
+	name   string
+	policy MutexPolicy
+}
+
+func (m _MutexGroup) apply(opts *options) {
+	opts.MutexGroup = m.name
+	opts.MutexPolicy = m.policy
+}
+
+// WithMutexGroup 同一个互斥组内的任务不会同时执行，组内已有任务在执行时按 policy 跳过或等待，name 为空表示不加入互斥组
+// 与 WithGroup 的分组无关，只影响执行；同一个任务自身的重叠仍由 RunMode 决定，
+// ModeJobSerial 下等待互斥组期间任务已处于运行状态，新的触发会因 SkipReasonSerial 被跳过
+func WithMutexGroup(name string, policy MutexPolicy) Option {
+	return _MutexGroup{name: name, policy: policy}
+}
+
+// mutex 返回互斥组对应的锁，第一次使用时创建
+func (s *Cron) mutex(name string) chan struct{} {
+	s.mutexLock.Lock()
+	defer s.mutexLock.Unlock()
+	m, ok := s.mutexes[name]
+	if !ok {
+		m = make(chan struct{}, 1)
+		s.mutexes[name] = m
+	}
+	return m
+}
+
+// exclusive 包装任务函数，持有互斥组的锁时才执行
+func (s *Cron) exclusive(id int, f execFunc, opt options) execFunc {
+	m := s.mutex(opt.MutexGroup)
+	return func(t trigger) {
+		if opt.MutexPolicy == MutexWait {
+			s.lock.RLock()
+			done := s.root.Done()
+			s.lock.RUnlock()
+
+			select {
+			case m <- struct{}{}:
+			case <-done:
+				return
+			}
+		} else {
+			select {
+			case m <- struct{}{}:
+			default:
+				s.unclaim(id, t)
+				s.skip(id, SkipReasonMutex, t.at)
+				return
+			}
+		}
+		defer func() { <-m }()
+		f(t)
+	}
+}
@@ -0,0 +1,114 @@
+package cron
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/robfig/cron/v3"
+)
+
+// ErrDuplicateName 任务名已经被其他任务使用
+var ErrDuplicateName = errors.New("cron: duplicate job name")
+
+type _Name string
+
+func (n _Name) apply(opts *options) {
+	opts.Name = string(n)
+}
+
+// WithName 设置任务名，之后可以通过 CallByName 等方法按名字操作任务
+// 任务名在同一个调度器内唯一：AddJob、AddNamedJob 等基于 spec 的方法遇到同名任务时
+// 用新的 spec、任务函数和配置原地替换它，id、统计信息和执行记录保持不变；
+// AddFixedDelayJob 等使用自定义调度的方法以及 ReloadJob 改名遇到重名时返回 ErrDuplicateName
+func WithName(name string) Option {
+	return _Name(name)
+}
+
+// AddNamedJob 添加带名字的任务，等同于 AddJobE 加上 WithName(name)
+// 已有同名任务时替换它并返回原来的 id
+func (s *Cron) AddNamedJob(name, spec string, f func(), options ...Option) (id int, err error) {
+	return s.AddJobE(spec, f, append(options, WithName(name))...)
+}
+
+// replaceNamed 原地替换与 opt.Name 同名的任务，调用方需持有写锁
+// 替换成功时释放写锁并返回 true，没有同名任务时返回 false，仍持有写锁
+func (s *Cron) replaceNamed(spec string, sched cron.Schedule, build func(id int) jobFunc, opt options) (int, bool) {
+	if opt.Name == "" {
+		return -1, false
+	}
+	id, ok := s.names[opt.Name]
+	e, loaded := s.load(id)
+	if !ok || !loaded {
+		return -1, false
+	}
+	s.replace(e, []string{spec}, []cron.Schedule{sched}, build(id), &opt)
+	l, kv := e.logger(s)
+	s.lock.Unlock()
+	l.Info("job replaced", kv...)
+
+	if opt.Immediately && atomic.LoadInt32(&s.state) != stateStopped {
+		go s.immediately(id)
+	}
+	return id, true
+}
+
+// GetJobByName 返回任务名对应的任务信息，名字不存在返回 false
+func (s *Cron) GetJobByName(name string) (JobInfo, bool) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	e, ok := s.load(s.names[name])
+	if _, named := s.names[name]; !named || !ok {
+		return JobInfo{}, false
+	}
+	return e.info(s), true
+}
+
+// lookup 返回名字对应的任务 id
+func (s *Cron) lookup(name string) (int, bool) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	id, ok := s.names[name]
+	return id, ok
+}
+
+// checkName 名字已被使用时返回 ErrDuplicateName，调用方需持有锁
+func (s *Cron) checkName(name string) error {
+	if name == "" {
+		return nil
+	}
+	if _, ok := s.names[name]; ok {
+		return fmt.Errorf("%w %q", ErrDuplicateName, name)
+	}
+	return nil
+}
+
+// CallByName 同 Call，名字不存在时不做任何操作
+func (s *Cron) CallByName(name string) {
+	if id, ok := s.lookup(name); ok {
+		s.Call(id)
+	}
+}
+
+// RemoveByName 同 RemoveJobByName
+func (s *Cron) RemoveByName(name string) {
+	s.RemoveJobByName(name)
+}
+
+// RemoveJobByName 删除任务名对应的任务，名字不存在返回 false
+func (s *Cron) RemoveJobByName(name string) bool {
+	id, ok := s.lookup(name)
+	if ok {
+		s.RemoveJob(id)
+	}
+	return ok
+}
+
+// GetStatusByName 同 GetStatus，名字不存在时返回 StatusReady
+func (s *Cron) GetStatusByName(name string) uint {
+	id, ok := s.lookup(name)
+	if !ok {
+		return StatusReady
+	}
+	return s.GetStatus(id)
+}
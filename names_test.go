@@ -0,0 +1,67 @@
+package cron
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestConcurrentAddSameName(t *testing.T) {
+	for round := 0; round < 50; round++ {
+		c := NewCron(WithLogger(DiscardLogger))
+		const n = 16
+		var (
+			wg    sync.WaitGroup
+			start = make(chan struct{})
+			ids   = make([]int, n)
+			errs  = make([]error, n)
+		)
+		for i := 0; i < n; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				<-start
+				ids[i], errs[i] = c.AddJobE("0 0 9 * * *", func() {}, WithName("report"))
+			}(i)
+		}
+		close(start)
+		wg.Wait()
+
+		for i := range ids {
+			if errs[i] != nil {
+				t.Fatalf("round %d: add %d failed: %v", round, i, errs[i])
+			}
+			if ids[i] != ids[0] {
+				t.Fatalf("round %d: ids = %v, want one shared id", round, ids)
+			}
+		}
+		if n := c.Count(); n != 1 {
+			t.Fatalf("round %d: %d jobs registered", round, n)
+		}
+		if got, ok := c.GetJobByName("report"); !ok || got.ID != ids[0] {
+			t.Fatalf("round %d: name resolves to %+v", round, got)
+		}
+	}
+}
+
+func TestAddNamedJobReplaces(t *testing.T) {
+	c := NewCron(WithLogger(DiscardLogger))
+	ran := make(chan string, 2)
+	id, err := c.AddNamedJob("report", "0 0 9 * * *", func() { ran <- "old" })
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := c.AddNamedJob("report", "0 30 10 * * *", func() { ran <- "new" })
+	if err != nil || again != id {
+		t.Fatalf("replace returned %d, %v; want %d", again, err, id)
+	}
+	if info, _ := c.jobInfo(id); info.Spec != "0 30 10 * * *" {
+		t.Errorf("spec = %q", info.Spec)
+	}
+	c.Call(id)
+	if got := receive(t, ran); got != "new" {
+		t.Errorf("Call ran the %s function", got)
+	}
+	if _, err := c.AddFixedDelayJobE(1e9, func() {}, WithName("report")); err == nil {
+		t.Error("custom schedule reused a taken name")
+	}
+}
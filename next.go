@@ -0,0 +1,116 @@
+package cron
+
+import (
+	"sort"
+	"time"
+)
+
+// NextRun 返回任务下一次触发的时间，分组任务取最早的一个
+// 返回的时间位于调度器的时区，见 WithLocation
+// 调度器尚未启动时按当前时间推算；暂停的任务或不会再触发的任务返回零值和 true
+// id 不存在返回零值和 false
+func (s *Cron) NextRun(id int) (time.Time, bool) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	e, ok := s.load(id)
+	if !ok {
+		return time.Time{}, false
+	}
+	return e.next(s), true
+}
+
+// NextRuns 返回任务接下来 n 次触发的时间，按时间排序，分组任务合并所有 spec 的触发时间
+// 暂停的任务、不会再触发的任务或 id 不存在时返回 nil；触发次数不足 n 次时只返回已有的部分
+func (s *Cron) NextRuns(id int, n int) []time.Time {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	e, ok := s.load(id)
+	if !ok || e.paused || n <= 0 {
+		return nil
+	}
+	var out []time.Time
+	now := s.now()
+	for i, entryID := range e.ids {
+		t, _ := s.entryTimes(entryID)
+		if t.IsZero() {
+			t = peekNext(e.scheds[i], now)
+		}
+		for k := 0; k < n && !t.IsZero(); k++ {
+			out = append(out, t.In(s.location))
+			t = peekNext(e.scheds[i], t)
+		}
+	}
+	return firstN(out, n)
+}
+
+// PreviewSpec 返回六段式 spec 从现在开始接下来 n 次触发的时间，用于注册之前检查 spec，
+// 按本地时区理解，spec 可以用 CRON_TZ= 指定时区；解析失败返回 *SpecError
+func PreviewSpec(spec string, n int) ([]time.Time, error) {
+	sched, err := secondParser.Parse(spec)
+	if err != nil {
+		return nil, &SpecError{Spec: spec, Err: err}
+	}
+	var out []time.Time
+	for t := sched.Next(time.Now()); len(out) < n && !t.IsZero(); t = sched.Next(t) {
+		out = append(out, t)
+	}
+	return out, nil
+}
+
+// firstN 排序去重后返回最早的 n 个时间
+func firstN(ts []time.Time, n int) []time.Time {
+	sort.Slice(ts, func(i, j int) bool {
+		return ts[i].Before(ts[j])
+	})
+	out := ts[:0]
+	for _, t := range ts {
+		if len(out) > 0 && out[len(out)-1].Equal(t) {
+			continue
+		}
+		if len(out) == n {
+			break
+		}
+		out = append(out, t)
+	}
+	return out
+}
+
+// PrevRun 返回任务上一次被调度触发的时间，分组任务取最晚的一个
+// 从未触发、暂停后尚未再次触发时返回零值和 true；id 不存在返回零值和 false
+func (s *Cron) PrevRun(id int) (time.Time, bool) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	e, ok := s.load(id)
+	if !ok {
+		return time.Time{}, false
+	}
+	return e.prev(s), true
+}
+
+// next 计算下一次触发时间，调用方需持有读锁
+func (e *entry) next(s *Cron) time.Time {
+	var next time.Time
+	now := s.now()
+	for i, entryID := range e.ids {
+		t, _ := s.entryTimes(entryID)
+		// robfig 在 Start 之前不会计算 Next
+		if t.IsZero() {
+			t = peekNext(e.scheds[i], now)
+		}
+		if !t.IsZero() && (next.IsZero() || t.Before(next)) {
+			next = t.In(s.location)
+		}
+	}
+	return next
+}
+
+// prev 计算上一次触发时间，调用方需持有读锁
+func (e *entry) prev(s *Cron) time.Time {
+	var prev time.Time
+	for _, entryID := range e.ids {
+		if _, t := s.entryTimes(entryID); t.After(prev) {
+			prev = t.In(s.location)
+		}
+	}
+	return prev
+}
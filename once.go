@@ -0,0 +1,55 @@
+package cron
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// AddOnceJob 添加只执行一次的任务，在 delay 之后执行
+// 见 AddAtJob
+func (s *Cron) AddOnceJob(delay time.Duration, f func(), options ...Option) (id int) {
+	return s.AddAtJob(s.clock.Now().Add(delay), f, options...)
+}
+
+// AddOnceJobE 同 AddOnceJob，但会返回失败原因
+func (s *Cron) AddOnceJobE(delay time.Duration, f func(), options ...Option) (id int, err error) {
+	return s.AddAtJobE(s.clock.Now().Add(delay), f, options...)
+}
+
+// AddAfterJob 添加在 d 之后执行一次的任务，与 AddOnceJob 相同
+// 指定时刻执行请使用 AddAtJob
+func (s *Cron) AddAfterJob(d time.Duration, f func(), options ...Option) (id int) {
+	return s.AddOnceJob(d, f, options...)
+}
+
+// AddAfterJobE 同 AddAfterJob，但会返回失败原因
+func (s *Cron) AddAfterJobE(d time.Duration, f func(), options ...Option) (id int, err error) {
+	return s.AddOnceJobE(d, f, options...)
+}
+
+// AddAtJob 添加在 at 执行一次的任务，执行之后任务会被自动删除，id 不再有效
+// at 已经过去时在调度器运行后立即执行；尚未 Start 时在 Start 之后执行
+// 通过 Call 手动执行同样算作这一次执行，执行后任务被删除
+// Recover、PanicHandler 等配置与普通任务一致，panic 或被跳过时任务同样会被删除
+func (s *Cron) AddAtJob(at time.Time, f func(), options ...Option) (id int) {
+	id, _ = s.AddAtJobE(at, f, options...)
+	return id
+}
+
+// AddAtJobE 同 AddAtJob，但会返回失败原因
+func (s *Cron) AddAtJobE(at time.Time, f func(), options ...Option) (id int, err error) {
+	spec := fmt.Sprintf("@at %s", at.Format(time.RFC3339))
+	s.warnStopped(spec)
+
+	id = s.genID()
+	// 触发结束后删除任务，被互斥组、分布式锁、限流等跳过的触发同样算作这一次，见 settle
+	once := &onceSchedule{at: at, then: func(time.Time) { s.RemoveJob(id) }}
+	err = s.addEntry(id, []string{spec}, []cron.Schedule{once}, plain(f), applyOptions(options...))
+	if err != nil {
+		return -1, err
+	}
+
+	return id, nil
+}
@@ -0,0 +1,114 @@
+package cron
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// waitRemoved 等待任务被删除，一次性任务在触发结束后才删除
+func waitRemoved(t *testing.T, c *Cron, id int) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if _, ok := c.NextRun(id); !ok {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("job %d was not removed", id)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestAtJobRunsOnceAndRemovesItself(t *testing.T) {
+	c, clk := newFakeCron(t)
+	ran := make(chan time.Time, 2)
+	at := testStart.Add(3 * time.Second)
+	id := c.AddAtJob(at, func() { ran <- clk.Now() })
+	c.Start()
+
+	blockUntil(t, clk, 1)
+	clk.Advance(2 * time.Second)
+	never(t, ran)
+	clk.Advance(time.Second)
+	if got := receive(t, ran); !got.Equal(at) {
+		t.Fatalf("ran at %v, want %v", got, at)
+	}
+	waitRemoved(t, c, id)
+	clk.Advance(time.Hour)
+	never(t, ran)
+}
+
+func TestOnceAndAfterJobsRemoveThemselves(t *testing.T) {
+	c, clk := newFakeCron(t)
+	ran := make(chan string, 2)
+	once := c.AddOnceJob(time.Second, func() { ran <- "once" })
+	after := c.AddAfterJob(2*time.Second, func() { ran <- "after" })
+	c.Start()
+
+	blockUntil(t, clk, 1)
+	clk.Advance(time.Second)
+	if got := receive(t, ran); got != "once" {
+		t.Fatalf("first run %q", got)
+	}
+	waitRemoved(t, c, once)
+	blockUntil(t, clk, 1)
+	clk.Advance(time.Second)
+	if got := receive(t, ran); got != "after" {
+		t.Fatalf("second run %q", got)
+	}
+	waitRemoved(t, c, after)
+	if n := c.Count(); n != 0 {
+		t.Errorf("%d jobs left", n)
+	}
+}
+
+func TestAtJobRemovedWhenSkipped(t *testing.T) {
+	c, clk := newFakeCron(t)
+	var skips skipRecorder
+	hold, release := make(chan struct{}), make(chan struct{})
+	var once sync.Once
+	unblock := func() { once.Do(func() { close(release) }) }
+	t.Cleanup(unblock)
+	busy := c.AddJob("0 0 0 1 1 *", func() {
+		close(hold)
+		<-release
+	}, WithMutexGroup("db", MutexSkip))
+	ran := make(chan struct{}, 1)
+	id := c.AddOnceJob(time.Second, func() { ran <- struct{}{} }, WithMutexGroup("db", MutexSkip), skips.option())
+	c.Start()
+
+	go c.Call(busy)
+	receive(t, hold)
+	blockUntil(t, clk, 1)
+	clk.Advance(time.Second)
+	waitRemoved(t, c, id)
+	never(t, ran)
+	if got := skips.get(); len(got) != 1 || got[0] != SkipReasonMutex {
+		t.Errorf("skips = %v, want [mutex]", got)
+	}
+	unblock()
+}
+
+func TestAtJobRemovedWhenRateLimited(t *testing.T) {
+	c, clk := newFakeCron(t)
+	ran := make(chan struct{}, 1)
+	// burst 为 0 时限流器不放行任何执行
+	id := c.AddAtJob(testStart.Add(time.Second), func() { ran <- struct{}{} }, WithRateLimit(1, 0))
+	c.Start()
+
+	blockUntil(t, clk, 1)
+	clk.Advance(time.Second)
+	waitRemoved(t, c, id)
+	never(t, ran)
+}
+
+func TestAtJobRemovedAfterCall(t *testing.T) {
+	c, _ := newFakeCron(t)
+	ran := make(chan struct{}, 1)
+	id := c.AddAtJob(testStart.Add(time.Hour), func() { ran <- struct{}{} })
+	c.Call(id)
+	receive(t, ran)
+	waitRemoved(t, c, id)
+}
@@ -0,0 +1,57 @@
+package cron
+
+import (
+	"fmt"
+	"runtime"
+)
+
+type _PanicHandler func(id int, recovered interface{}, stack []byte)
+
+func (f _PanicHandler) apply(opts *options) {
+	opts.PanicHandler = f
+}
+
+func (f _PanicHandler) applyCron(opts *cronOptions) {
+	opts.PanicHandler = f
+}
+
+// WithPanicHandler 设置捕获到 panic 时的回调，stack 为发生 panic 的 goroutine 调用栈
+// 传给 NewCron 时作为所有任务的默认回调，添加任务时传入会覆盖它；都未设置时交给 Logger
+// 回调自身的 panic 会被捕获，不会影响调度器；无论是否设置，panic 都会计入执行记录和 Metrics
+func WithPanicHandler(f func(id int, recovered interface{}, stack []byte)) CommonOption {
+	return _PanicHandler(f)
+}
+
+// handlePanic 将捕获到的 panic 交给 PanicHandler，未设置时记录到日志
+// 需要在 recover 所在的 defer 中调用，才能取到发生 panic 时的调用栈
+func (s *Cron) handlePanic(id int, opt options, recovered interface{}) {
+	l, kv := s.jobLogger(id)
+	handler := opt.PanicHandler
+	if handler == nil {
+		handler = s.panicHandler
+	}
+	if handler == nil {
+		l.Error(fmt.Errorf("%v", recovered), "job panicked", kv...)
+		return
+	}
+	defer func() {
+		if err := recover(); err != nil {
+			l.Error(fmt.Errorf("%v", err), "PanicHandler panicked", kv...)
+		}
+	}()
+
+	handler(id, recovered, stack())
+}
+
+// recoverPanic 捕获 panic 并交给 handlePanic，需要直接 defer 调用
+func (s *Cron) recoverPanic(id int, opt options) {
+	if err := recover(); err != nil {
+		s.handlePanic(id, opt, err)
+	}
+}
+
+// stack 返回当前 goroutine 的调用栈，在 recover 所在的 defer 中调用时包含发生 panic 的位置
+func stack() []byte {
+	buf := make([]byte, 64<<10)
+	return buf[:runtime.Stack(buf, false)]
+}
@@ -0,0 +1,70 @@
+package cron
+
+// pause 暂停任务，保留任务但不再触发，已暂停或不存在返回 false
+// 正在执行的任务会继续执行，AddJobContext 任务的 ctx 会被取消
+func (s *Cron) pause(id int) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	e, ok := s.load(id)
+	if !ok || e.paused {
+		return false
+	}
+	e.unschedule(s.c)
+	e.paused = true
+	e.runs.cancelAll()
+	return true
+}
+
+// resume 恢复已暂停的任务，使用原来的调度重新注册，未暂停或不存在返回 false
+func (s *Cron) resume(id int) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	e, ok := s.load(id)
+	if !ok || !e.paused {
+		return false
+	}
+	for _, sched := range e.scheds {
+		if o, ok := sched.(*onceSchedule); ok {
+			o.rewind()
+		}
+	}
+	e.paused = false
+	e.runs.reopen()
+	e.schedule(s)
+	return true
+}
+
+// PauseJob 暂停任务，id 保持不变，GetStatus 返回 StatusPaused
+// 已暂停或不存在的任务不做任何操作；正在进行的执行会继续，但 AddJobContext 任务的 ctx 会被取消
+// 暂停期间 Call 仍然可以手动执行，ctx 任务收到的 ctx 已经被取消
+func (s *Cron) PauseJob(id int) {
+	s.pause(id)
+}
+
+// ResumeJob 按原来的 spec 和配置恢复已暂停的任务，未暂停或不存在的任务不做任何操作
+func (s *Cron) ResumeJob(id int) {
+	s.resume(id)
+}
+
+// PauseWhere 暂停所有满足条件的任务，返回实际暂停的数量
+// 条件基于调用时的同一份快照判断，快照之后新增的任务不受影响
+func (s *Cron) PauseWhere(match func(JobStats) bool) int {
+	n := 0
+	for _, st := range s.snapshot() {
+		if match(st) && s.pause(st.ID) {
+			n++
+		}
+	}
+	return n
+}
+
+// ResumeWhere 恢复所有满足条件的任务，返回实际恢复的数量
+func (s *Cron) ResumeWhere(match func(JobStats) bool) int {
+	n := 0
+	for _, st := range s.snapshot() {
+		if match(st) && s.resume(st.ID) {
+			n++
+		}
+	}
+	return n
+}
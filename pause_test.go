@@ -0,0 +1,87 @@
+package cron
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPauseResumeKeepsID(t *testing.T) {
+	c, clk := newFakeCron(t)
+	ran := make(chan firing, 4)
+	id := c.AddJob("0 * * * * *", recordAs(clk, ran, "job"))
+	c.Start()
+
+	c.PauseJob(id)
+	if got := c.GetStatus(id); got != StatusPaused {
+		t.Errorf("status = %d, want StatusPaused", got)
+	}
+	if next, _ := c.NextRun(id); !next.IsZero() {
+		t.Errorf("paused job still scheduled at %v", next)
+	}
+	clk.Advance(time.Minute)
+	never(t, ran)
+
+	// 恢复后 id 不变，按原来的 spec 继续触发，现在是 09:00:55
+	c.ResumeJob(id)
+	if got := c.GetStatus(id); got != StatusReady {
+		t.Errorf("status = %d, want StatusReady", got)
+	}
+	want := time.Date(2024, 1, 1, 9, 1, 0, 0, time.UTC)
+	if next, ok := c.NextRun(id); !ok || !next.Equal(want) {
+		t.Errorf("NextRun = %v, want %v", next, want)
+	}
+	blockUntil(t, clk, 1)
+	clk.Advance(5 * time.Second)
+	if f := receive(t, ran); !f.at.Equal(want) {
+		t.Errorf("fired at %v, want %v", f.at, want)
+	}
+	if n := c.Count(); n != 1 {
+		t.Errorf("%d jobs, want 1", n)
+	}
+}
+
+func TestPauseResumeNoop(t *testing.T) {
+	c := NewCron(WithLogger(DiscardLogger))
+	id := c.AddJob("0 0 9 * * *", func() {})
+	// 未暂停的任务恢复、已暂停的任务再次暂停、不存在的任务都不做任何操作
+	c.ResumeJob(id)
+	c.PauseJob(42)
+	c.ResumeJob(42)
+	if got := c.GetStatus(id); got != StatusReady {
+		t.Errorf("status = %d, want StatusReady", got)
+	}
+	c.PauseJob(id)
+	c.PauseJob(id)
+	c.ResumeJob(id)
+	if got := c.GetStatus(id); got != StatusReady {
+		t.Errorf("status = %d after pausing twice and resuming once", got)
+	}
+}
+
+func TestPauseKeepsSpecAndOptions(t *testing.T) {
+	c := NewCron(WithLogger(DiscardLogger))
+	ran := make(chan struct{}, 2)
+	id := c.AddJob("0 30 10 * * *", func() { ran <- struct{}{} },
+		WithName("report"), WithGroup("daily"), WithRunMode(ModeJobParallel), WithTimeout(time.Minute))
+	c.PauseJob(id)
+
+	info, ok := c.GetJobByName("report")
+	if !ok || info.ID != id || info.Spec != "0 30 10 * * *" || info.Group != "daily" || info.Status != StatusPaused {
+		t.Errorf("paused job = %+v", info)
+	}
+	// 暂停期间仍然可以手动执行
+	c.Call(id)
+	receive(t, ran)
+
+	c.ResumeJob(id)
+	_, opt, _ := c.loadOptions(id)
+	if opt.Name != "report" || opt.RunMode != ModeJobParallel || opt.Timeout != time.Minute {
+		t.Errorf("options after resume = %+v", opt)
+	}
+	if runs := c.NextRuns(id, 1); len(runs) != 1 || runs[0].Hour() != 10 || runs[0].Minute() != 30 {
+		t.Errorf("next runs after resume = %v", runs)
+	}
+	if st, _ := c.Stats(id); st.Runs != 1 {
+		t.Errorf("stats reset by pause: %+v", st)
+	}
+}
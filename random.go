@@ -0,0 +1,85 @@
+package cron
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// monthWindow 月任务随机窗口的范围，取最短的月份保证每个月都能触发
+const monthWindow = 28 * 24 * time.Hour
+
+type randomWindow struct {
+	anchor time.Duration
+	spread time.Duration
+}
+
+func (w *randomWindow) apply(opts *options) {
+	opts.RandomWindow = w
+}
+
+// WithRandomWindow 在 anchor 前后 spread 范围内随机选择执行时间，精确到秒
+// anchor 是相对于辅助方法周期起点的偏移，比如 AddHourJob 中
+// WithRandomWindow(30*time.Minute, 5*time.Minute) 表示在每个小时的 25 分到 35 分之间执行
+// 各辅助方法的周期：AddMinuteJob 为一分钟，AddHourJob 为一小时，
+// AddDayJob 和 AddWeekJob 为一天，AddMonthJob 为 28 天
+// 窗口超出周期范围时辅助方法返回 -1
+func WithRandomWindow(anchor time.Duration, spread time.Duration) Option {
+	return &randomWindow{anchor: anchor, spread: spread}
+}
+
+// offset 在窗口内随机取一个偏移，窗口不在 [0, unit) 内返回 false
+func (w *randomWindow) offset(unit time.Duration, r *lockedRand) (time.Duration, bool) {
+	lo, hi := w.anchor-w.spread, w.anchor+w.spread
+	if w.spread < 0 || lo < 0 || hi >= unit {
+		return 0, false
+	}
+	n := int64((hi-lo)/time.Second) + 1
+	return lo.Truncate(time.Second) + time.Duration(r.Int63n(n))*time.Second, true
+}
+
+// splitOffset 将偏移拆分为天、时、分、秒
+func splitOffset(off time.Duration) (day, hour, min, sec int) {
+	total := int(off / time.Second)
+	return total / 86400, total % 86400 / 3600, total % 3600 / 60, total % 60
+}
+
+// lockedRand 并发安全的随机数生成器，rand.Rand 本身不能并发使用
+// 每个 Cron 使用自己的实例，不与全局随机数生成器争用锁
+type lockedRand struct {
+	mu sync.Mutex
+	r  *rand.Rand
+}
+
+func newLockedRand(src rand.Source) *lockedRand {
+	if src == nil {
+		src = rand.NewSource(time.Now().UnixNano())
+	}
+	return &lockedRand{r: rand.New(src)}
+}
+
+func (l *lockedRand) Intn(n int) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.r.Intn(n)
+}
+
+func (l *lockedRand) Int63n(n int64) int64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.r.Int63n(n)
+}
+
+type _RandSource struct {
+	rand.Source
+}
+
+func (r _RandSource) applyCron(opts *cronOptions) {
+	opts.RandSource = r.Source
+}
+
+// WithRandSource 设置 WithRandom、WithRandomWindow 和 WithJitter 使用的随机源
+// 默认使用以当前时间为种子的随机源，测试中可以传入固定种子的随机源得到确定的 spec
+func WithRandSource(src rand.Source) CronOption {
+	return _RandSource{src}
+}
@@ -0,0 +1,108 @@
+package cron
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestRandomHelperSpecs(t *testing.T) {
+	random := WithRandom(true)
+	noop := func() {}
+	tests := []struct {
+		name string
+		add  func(c *Cron) (int, string)
+		want string
+	}{
+		{"second", func(c *Cron) (int, string) { return c.AddSecondJobWithSpec(10, noop, random) }, "5/10 * * * * *"},
+		{"minute", func(c *Cron) (int, string) { return c.AddMinuteJobWithSpec(5, noop, random) }, "5 */5 * * * *"},
+		{"minute window", func(c *Cron) (int, string) {
+			return c.AddMinuteJobWithSpec(5, noop, WithRandomWindow(30*time.Second, 10*time.Second))
+		}, "30 */5 * * * *"},
+		{"hour", func(c *Cron) (int, string) { return c.AddHourJobWithSpec(2, noop, random) }, "5 47 */2 * * *"},
+		{"hour window", func(c *Cron) (int, string) {
+			return c.AddHourJobWithSpec(2, noop, WithRandomWindow(30*time.Minute, 5*time.Minute))
+		}, "49 33 */2 * * *"},
+		{"day", func(c *Cron) (int, string) { return c.AddDayJobWithSpec(3, noop, random) }, "5 47 20 */3 * *"},
+		{"day window", func(c *Cron) (int, string) {
+			return c.AddDayJobWithSpec(3, noop, WithRandomWindow(9*time.Hour, time.Hour))
+		}, "4 8 9 */3 * *"},
+		{"month", func(c *Cron) (int, string) { return c.AddMonthJobWithSpec(1, noop, random) }, "5 47 20 23 */1 *"},
+		{"month window", func(c *Cron) (int, string) {
+			return c.AddMonthJobWithSpec(1, noop, WithRandomWindow(14*24*time.Hour, time.Hour))
+		}, "4 8 0 15 */1 *"},
+		{"week", func(c *Cron) (int, string) { return c.AddWeekJobWithSpec(1, noop, random) }, "5 47 20 * * 1"},
+		{"week window", func(c *Cron) (int, string) {
+			return c.AddWeekJobWithSpec(1, noop, WithRandomWindow(9*time.Hour, time.Hour))
+		}, "4 8 9 * * 1"},
+		{"without random", func(c *Cron) (int, string) { return c.AddHourJobWithSpec(2, noop) }, "0 0 */2 * * *"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewCron(WithRandSource(rand.NewSource(42)), WithLogger(DiscardLogger))
+			id, spec := tt.add(c)
+			if spec != tt.want {
+				t.Errorf("spec = %q, want %q", spec, tt.want)
+			}
+			if id < 0 {
+				t.Fatalf("helper rejected its own spec %q", spec)
+			}
+			if _, err := secondParser.Parse(spec); err != nil {
+				t.Errorf("generated spec %q does not parse: %v", spec, err)
+			}
+
+			// 相同的种子得到相同的 spec
+			again := NewCron(WithRandSource(rand.NewSource(42)), WithLogger(DiscardLogger))
+			if _, spec2 := tt.add(again); spec2 != spec {
+				t.Errorf("same seed produced %q and %q", spec, spec2)
+			}
+		})
+	}
+}
+
+func TestRandomWindowOutOfRange(t *testing.T) {
+	c := NewCron(WithRandSource(rand.NewSource(1)), WithLogger(DiscardLogger))
+	noop := func() {}
+	if id, spec := c.AddHourJobWithSpec(1, noop, WithRandomWindow(58*time.Minute, 5*time.Minute)); id != -1 || spec != "" {
+		t.Errorf("window past the hour: id %d, spec %q", id, spec)
+	}
+	if id, _ := c.AddMinuteJobWithSpec(1, noop, WithRandomWindow(2*time.Second, 5*time.Second)); id != -1 {
+		t.Errorf("window before the minute: id %d", id)
+	}
+	if id, _ := c.AddDayJobWithSpec(1, noop, WithRandomWindow(time.Hour, -time.Minute)); id != -1 {
+		t.Errorf("negative spread: id %d", id)
+	}
+}
+
+func TestRandomOffsetStaysInWindow(t *testing.T) {
+	r := newLockedRand(rand.NewSource(7))
+	w := &randomWindow{anchor: 30 * time.Minute, spread: 5 * time.Minute}
+	for i := 0; i < 1000; i++ {
+		off, ok := w.offset(time.Hour, r)
+		if !ok || off < 25*time.Minute || off > 35*time.Minute || off%time.Second != 0 {
+			t.Fatalf("offset %v, %v", off, ok)
+		}
+	}
+}
+
+func TestRandomMonthDayFitsFebruary(t *testing.T) {
+	r := newLockedRand(rand.NewSource(1))
+	opt := applyOptions(WithRandom(true))
+	seen := map[int]bool{}
+	for i := 0; i < 2000; i++ {
+		var sec, min, hour, day, mon int
+		spec := monthSpec(1, opt, r)
+		if _, err := fmt.Sscanf(spec, "%d %d %d %d */%d *", &sec, &min, &hour, &day, &mon); err != nil {
+			t.Fatalf("%q: %v", spec, err)
+		}
+		// 每个月都有的日期，二月也会触发
+		if day < 1 || day > 28 {
+			t.Fatalf("%q picks day %d", spec, day)
+		}
+		seen[day] = true
+	}
+	if len(seen) != 28 {
+		t.Errorf("picked %d distinct days, want all 28", len(seen))
+	}
+}
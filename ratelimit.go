@@ -0,0 +1,22 @@
+package cron
+
+import (
+	"golang.org/x/time/rate"
+)
+
+type _RateLimit struct {
+	limit rate.Limit
+	burst int
+}
+
+func (r _RateLimit) apply(opts *options) {
+	opts.RateLimiter = rate.NewLimiter(r.limit, r.burst)
+}
+
+// WithRateLimit 限制任务的执行频率，平均每秒最多 r 次，最多连续执行 burst 次，
+// 超出的触发会被跳过，reason 为 SkipReasonRateLimit
+// 定时触发、立即执行、Call 和依赖触发共用同一个限额，适合 spec 来自用户配置、需要防止过于频繁执行的场景
+// 同一个 Option 用于多个任务时每个任务各自计数；UpdateJob、ReloadJob 传入该配置时限额重新开始计算
+func WithRateLimit(r rate.Limit, burst int) Option {
+	return _RateLimit{limit: r, burst: burst}
+}
@@ -0,0 +1,65 @@
+package cron
+
+import (
+	"testing"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+func TestRateLimitSkipsBeyondBurst(t *testing.T) {
+	c := NewCron(WithLogger(DiscardLogger))
+	var skips skipRecorder
+	runs := 0
+	id := c.AddJob("0 0 9 * * *", func() { runs++ }, WithRateLimit(rate.Every(time.Hour), 2), skips.option())
+
+	for i := 0; i < 4; i++ {
+		c.Call(id)
+	}
+	if runs != 2 {
+		t.Errorf("%d runs, want the burst of 2", runs)
+	}
+	if got := skips.get(); len(got) != 2 || got[0] != SkipReasonRateLimit || got[1] != SkipReasonRateLimit {
+		t.Errorf("skips = %v, want two rate skips", got)
+	}
+	if st, _ := c.Stats(id); st.Runs != 2 || st.Skipped != 2 {
+		t.Errorf("stats = %+v", st)
+	}
+}
+
+func TestRateLimitRefills(t *testing.T) {
+	c := NewCron(WithLogger(DiscardLogger))
+	runs := 0
+	id := c.AddJob("0 0 9 * * *", func() { runs++ }, WithRateLimit(rate.Every(20*time.Millisecond), 1))
+	c.Call(id)
+	c.Call(id)
+	time.Sleep(30 * time.Millisecond)
+	c.Call(id)
+	if runs != 2 {
+		t.Errorf("%d runs, want 2", runs)
+	}
+}
+
+func TestRateLimitPerJob(t *testing.T) {
+	c := NewCron(WithLogger(DiscardLogger))
+	limit := WithRateLimit(rate.Every(time.Hour), 1)
+	runs := map[string]int{}
+	a := c.AddJob("0 0 9 * * *", func() { runs["a"]++ }, limit)
+	b := c.AddJob("0 0 9 * * *", func() { runs["b"]++ }, limit)
+	// 同一个 Option 用于两个任务，各自计数
+	c.Call(a)
+	c.Call(a)
+	c.Call(b)
+	if runs["a"] != 1 || runs["b"] != 1 {
+		t.Errorf("runs = %v, want one each", runs)
+	}
+
+	// 重新加载后限额重新开始计算
+	if err := c.ReloadJob(a, "0 0 9 * * *", limit); err != nil {
+		t.Fatal(err)
+	}
+	c.Call(a)
+	if runs["a"] != 2 {
+		t.Errorf("%d runs of a after reload, want 2", runs["a"])
+	}
+}
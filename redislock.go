@@ -0,0 +1,89 @@
+package cron
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+// RedisClient RedisLocker 需要的 Redis 命令，本包不依赖具体的 Redis 客户端，
+// 以 go-redis 为例，适配方式如下：
+//
+//	type client struct{ *redis.Client }
+//
+//	func (c client) SetNX(ctx context.Context, key, value string, ttl time.Duration) (bool, error) {
+//		return c.Client.SetNX(ctx, key, value, ttl).Result()
+//	}
+//
+//	func (c client) Eval(ctx context.Context, script string, keys []string, args ...interface{}) (interface{}, error) {
+//		return c.Client.Eval(ctx, script, keys, args...).Result()
+//	}
+type RedisClient interface {
+	// SetNX 对应 SET key value NX PX ttl，key 已存在时返回 false
+	SetNX(ctx context.Context, key, value string, ttl time.Duration) (bool, error)
+	// Eval 对应 EVAL script len(keys) keys... args...
+	Eval(ctx context.Context, script string, keys []string, args ...interface{}) (interface{}, error)
+}
+
+// unlockScript 只删除自己持有的锁，避免执行超过 TTL 后删掉其他实例重新获取的锁
+const unlockScript = `if redis.call("get", KEYS[1]) == ARGV[1] then return redis.call("del", KEYS[1]) else return 0 end`
+
+// RedisLocker 基于 Redis SET NX 的 Locker 实现
+// 每次获取锁时写入随机值，释放时只删除值相同的 key；
+// 随机值按 key 和本次执行记录，执行超过 TTL 后同一进程内重叠的执行重新获取了锁，
+// 先结束的执行释放锁时不会删掉后者的锁；不经过调度器直接调用时同一个 key 只记录最近一次的随机值
+type RedisLocker struct {
+	client RedisClient
+	mu     sync.Mutex
+	tokens map[lockHolder]string
+}
+
+// lockHolder 持有锁的 key 和执行
+type lockHolder struct {
+	key string
+	run *lockRun
+}
+
+// NewRedisLocker 创建使用 client 的 RedisLocker
+func NewRedisLocker(client RedisClient) *RedisLocker {
+	return &RedisLocker{client: client, tokens: make(map[lockHolder]string)}
+}
+
+func (l *RedisLocker) TryLock(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	token, err := newToken()
+	if err != nil {
+		return false, err
+	}
+	ok, err := l.client.SetNX(ctx, key, token, ttl)
+	if err != nil || !ok {
+		return false, err
+	}
+	l.mu.Lock()
+	l.tokens[lockHolder{key: key, run: lockRunOf(ctx)}] = token
+	l.mu.Unlock()
+	return true, nil
+}
+
+func (l *RedisLocker) Unlock(ctx context.Context, key string) error {
+	holder := lockHolder{key: key, run: lockRunOf(ctx)}
+	l.mu.Lock()
+	token, ok := l.tokens[holder]
+	delete(l.tokens, holder)
+	l.mu.Unlock()
+	if !ok {
+		return nil
+	}
+	_, err := l.client.Eval(ctx, unlockScript, []string{key}, token)
+	return err
+}
+
+// newToken 生成锁的随机值
+func newToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
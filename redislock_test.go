@@ -0,0 +1,157 @@
+package cron
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeRedis 只实现 RedisLocker 用到的命令，expire 模拟 key 过期
+type fakeRedis struct {
+	mu   sync.Mutex
+	data map[string]string
+}
+
+func newFakeRedis() *fakeRedis {
+	return &fakeRedis{data: make(map[string]string)}
+}
+
+func (r *fakeRedis) SetNX(_ context.Context, key, value string, _ time.Duration) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.data[key]; ok {
+		return false, nil
+	}
+	r.data[key] = value
+	return true, nil
+}
+
+func (r *fakeRedis) Eval(_ context.Context, script string, keys []string, args ...interface{}) (interface{}, error) {
+	if script != unlockScript {
+		panic("unexpected script")
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.data[keys[0]] == args[0].(string) {
+		delete(r.data, keys[0])
+		return int64(1), nil
+	}
+	return int64(0), nil
+}
+
+func (r *fakeRedis) expire(key string) {
+	r.mu.Lock()
+	delete(r.data, key)
+	r.mu.Unlock()
+}
+
+func (r *fakeRedis) get(key string) (string, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	v, ok := r.data[key]
+	return v, ok
+}
+
+func runCtx() (lock, unlock context.Context) {
+	run := &lockRun{}
+	return context.WithValue(context.Background(), lockRunKey{}, run), context.WithValue(context.Background(), lockRunKey{}, run)
+}
+
+func TestRedisLockerOverlappingRuns(t *testing.T) {
+	redis := newFakeRedis()
+	l := NewRedisLocker(redis)
+	lock1, unlock1 := runCtx()
+	lock2, unlock2 := runCtx()
+
+	if ok, err := l.TryLock(lock1, "cron:job", time.Second); !ok || err != nil {
+		t.Fatalf("first TryLock = %v, %v", ok, err)
+	}
+	if ok, _ := l.TryLock(lock2, "cron:job", time.Second); ok {
+		t.Fatal("second TryLock succeeded while the lock is held")
+	}
+
+	// 第一次执行超过 TTL，锁过期后被第二次执行获取
+	redis.expire("cron:job")
+	if ok, err := l.TryLock(lock2, "cron:job", time.Second); !ok || err != nil {
+		t.Fatalf("TryLock after expiry = %v, %v", ok, err)
+	}
+	second, _ := redis.get("cron:job")
+
+	if err := l.Unlock(unlock1, "cron:job"); err != nil {
+		t.Fatal(err)
+	}
+	if v, ok := redis.get("cron:job"); !ok || v != second {
+		t.Fatalf("first run released the second run's lock: %q, %v", v, ok)
+	}
+	if err := l.Unlock(unlock2, "cron:job"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := redis.get("cron:job"); ok {
+		t.Fatal("second run did not release its lock")
+	}
+}
+
+func TestRedisLockerWithoutRun(t *testing.T) {
+	redis := newFakeRedis()
+	l := NewRedisLocker(redis)
+	ctx := context.Background()
+	if ok, _ := l.TryLock(ctx, "k", time.Second); !ok {
+		t.Fatal("TryLock failed")
+	}
+	if err := l.Unlock(ctx, "k"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := redis.get("k"); ok {
+		t.Fatal("lock not released")
+	}
+	if err := l.Unlock(ctx, "k"); err != nil {
+		t.Errorf("Unlock without holding the lock: %v", err)
+	}
+}
+
+func TestDistributedLockPerRun(t *testing.T) {
+	redis := newFakeRedis()
+	locker := notifyLocker{RedisLocker: NewRedisLocker(redis), unlocked: make(chan struct{}, 2)}
+	c := NewCron(WithLogger(DiscardLogger))
+	c.Start()
+	defer c.Stop()
+
+	started := make(chan struct{}, 2)
+	release := make(chan struct{}, 2)
+	id := c.AddJob("0 0 9 * * *", func() {
+		started <- struct{}{}
+		<-release
+	}, WithName("job"), WithRunMode(ModeTimeFirst), WithDistributedLock(locker))
+
+	go c.Call(id)
+	receive(t, started)
+	redis.expire("cron:job")
+	go c.Call(id)
+	receive(t, started)
+	second, _ := redis.get("cron:job")
+
+	// 第一次执行结束，不能释放第二次执行持有的锁
+	release <- struct{}{}
+	receive(t, locker.unlocked)
+	if v, ok := redis.get("cron:job"); !ok || v != second {
+		t.Fatalf("lock after first run finished: %q, %v", v, ok)
+	}
+	release <- struct{}{}
+	receive(t, locker.unlocked)
+	if _, ok := redis.get("cron:job"); ok {
+		t.Fatal("second run did not release its lock")
+	}
+}
+
+// notifyLocker 每次 Unlock 之后通知
+type notifyLocker struct {
+	*RedisLocker
+	unlocked chan struct{}
+}
+
+func (l notifyLocker) Unlock(ctx context.Context, key string) error {
+	err := l.RedisLocker.Unlock(ctx, key)
+	l.unlocked <- struct{}{}
+	return err
+}
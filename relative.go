@@ -0,0 +1,113 @@
+package cron
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// relativeSchedule 在参考任务每次触发时间上加 offset 的调度
+// robfig 在自己的 goroutine 中调用 Next，而 Cron 持有锁时会向 robfig 发送请求，
+// 所以这里不能访问 Cron 的锁，参考任务的调度保存一份副本，参考任务重新调度时同步更新
+type relativeSchedule struct {
+	mu     sync.Mutex
+	ref    int
+	offset time.Duration
+	base   []cron.Schedule
+}
+
+func (r *relativeSchedule) Next(t time.Time) time.Time {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var next time.Time
+	for _, sched := range r.base {
+		n := peekNext(sched, t.Add(-r.offset))
+		if !n.IsZero() && (next.IsZero() || n.Before(next)) {
+			next = n
+		}
+	}
+	if next.IsZero() {
+		return next
+	}
+	return next.Add(r.offset)
+}
+
+func (r *relativeSchedule) setBase(base []cron.Schedule) {
+	r.mu.Lock()
+	r.base = append([]cron.Schedule(nil), base...)
+	r.mu.Unlock()
+}
+
+// AddRelativeJob 添加相对于另一个任务触发时间的任务
+// 每次在 refID 任务触发时间加上 offset 时执行，offset 为负数表示提前执行，
+// 比如 offset 为 -5*time.Minute 表示在 refID 每次触发前 5 分钟执行
+// refID 重新调度后同步更新；refID 被删除时该任务也会被删除；refID 暂停不影响该任务
+// refID 不存在返回 -1
+func (s *Cron) AddRelativeJob(refID int, offset time.Duration, f func(), options ...Option) (id int) {
+	id, _ = s.AddRelativeJobE(refID, offset, f, options...)
+	return id
+}
+
+// AddRelativeJobE 同 AddRelativeJob，但会返回失败原因，refID 不存在返回 ErrNotFound
+func (s *Cron) AddRelativeJobE(refID int, offset time.Duration, f func(), options ...Option) (id int, err error) {
+	spec := fmt.Sprintf("@relative %d %v", refID, offset)
+	s.warnStopped(spec)
+
+	s.lock.RLock()
+	ref, ok := s.load(refID)
+	var base []cron.Schedule
+	if ok {
+		base = ref.scheds
+	}
+	s.lock.RUnlock()
+	if !ok {
+		return -1, ErrNotFound
+	}
+
+	sched := &relativeSchedule{ref: refID, offset: offset}
+	sched.setBase(base)
+
+	id = s.genID()
+	if err = s.addEntry(id, []string{spec}, []cron.Schedule{sched}, plain(f), applyOptions(options...)); err != nil {
+		return -1, err
+	}
+
+	return id, nil
+}
+
+// relatives 返回参考或依赖 refID 的任务，调用方需持有锁
+func (s *Cron) relatives(refID int) []*entry {
+	var out []*entry
+	s.entry.Range(func(_, value interface{}) bool {
+		e := value.(*entry)
+		for _, sched := range e.scheds {
+			if r, ok := sched.(*relativeSchedule); ok && r.ref == refID {
+				out = append(out, e)
+				break
+			}
+			if d, ok := sched.(*dependsSchedule); ok && d.dependsOn(refID) {
+				out = append(out, e)
+				break
+			}
+		}
+		return true
+	})
+	return out
+}
+
+// refreshRelatives 参考任务的调度变化后，更新并重新注册相对任务，调用方需持有写锁
+func (s *Cron) refreshRelatives(ref *entry) {
+	for _, e := range s.relatives(ref.id) {
+		for _, sched := range e.scheds {
+			if r, ok := sched.(*relativeSchedule); ok && r.ref == ref.id {
+				r.setBase(ref.scheds)
+			}
+		}
+		if !e.paused {
+			e.unschedule(s.c)
+			e.schedule(s)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package cron
+
+import (
+	"testing"
+	"time"
+)
+
+// firing 任务名和执行时的时间
+type firing struct {
+	name string
+	at   time.Time
+}
+
+// stepFires 每次前进一秒，共 n 秒，返回期间的所有执行
+func stepFires(t *testing.T, clk *FakeClock, ran <-chan firing, n int) []firing {
+	t.Helper()
+	var out []firing
+	for i := 0; i < n; i++ {
+		blockUntil(t, clk, 1)
+		clk.Advance(time.Second)
+		for {
+			select {
+			case f := <-ran:
+				out = append(out, f)
+				continue
+			case <-time.After(50 * time.Millisecond):
+			}
+			break
+		}
+	}
+	return out
+}
+
+func recordAs(clk *FakeClock, ran chan<- firing, name string) func() {
+	return func() { ran <- firing{name, clk.Now()} }
+}
+
+func TestRelativeJobOffsets(t *testing.T) {
+	c, clk := newFakeCron(t)
+	ran := make(chan firing, 16)
+	// 参考任务在每分钟第 0 秒执行，测试从 08:59:55 开始
+	ref := c.AddJob("0 * * * * *", recordAs(clk, ran, "ref"))
+	after, err := c.AddRelativeJobE(ref, 2*time.Second, recordAs(clk, ran, "after"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	before, err := c.AddRelativeJobE(ref, -3*time.Second, recordAs(clk, ran, "before"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	nine := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	if next, _ := c.NextRun(after); !next.Equal(nine.Add(2 * time.Second)) {
+		t.Errorf("positive offset NextRun = %v", next)
+	}
+	if next, _ := c.NextRun(before); !next.Equal(nine.Add(-3 * time.Second)) {
+		t.Errorf("negative offset NextRun = %v", next)
+	}
+	c.Start()
+
+	got := stepFires(t, clk, ran, 7)
+	want := []firing{
+		{"before", nine.Add(-3 * time.Second)},
+		{"ref", nine},
+		{"after", nine.Add(2 * time.Second)},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("fires = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i].name != want[i].name || !got[i].at.Equal(want[i].at) {
+			t.Errorf("fire %d = %v, want %v", i, got[i], want[i])
+		}
+	}
+
+	// 下一分钟同样按偏移执行
+	if next, _ := c.NextRun(before); !next.Equal(nine.Add(time.Minute - 3*time.Second)) {
+		t.Errorf("next negative offset = %v", next)
+	}
+}
+
+func TestRelativeJobFollowsReschedule(t *testing.T) {
+	c, _ := newFakeCron(t)
+	ref := c.AddJob("0 0 9 * * *", func() {})
+	rel := c.AddRelativeJob(ref, -time.Minute, func() {})
+	if err := c.RescheduleJob(ref, "0 30 10 * * *"); err != nil {
+		t.Fatal(err)
+	}
+	want := time.Date(2024, 1, 1, 10, 29, 0, 0, time.UTC)
+	if next, _ := c.NextRun(rel); !next.Equal(want) {
+		t.Errorf("NextRun after reschedule = %v, want %v", next, want)
+	}
+}
+
+func TestRelativeJobRemovedWithReference(t *testing.T) {
+	c, clk := newFakeCron(t)
+	ran := make(chan firing, 4)
+	ref := c.AddJob("0 * * * * *", func() {})
+	rel := c.AddRelativeJob(ref, time.Second, recordAs(clk, ran, "rel"))
+	c.Start()
+
+	c.RemoveJob(ref)
+	if _, ok := c.NextRun(rel); ok {
+		t.Fatal("relative job survived its reference")
+	}
+	clk.Advance(time.Minute)
+	never(t, ran)
+}
+
+func TestRelativeJobIgnoresPausedReference(t *testing.T) {
+	c, clk := newFakeCron(t)
+	ran := make(chan firing, 4)
+	ref := c.AddJob("0 * * * * *", recordAs(clk, ran, "ref"))
+	c.AddRelativeJob(ref, time.Second, recordAs(clk, ran, "rel"))
+	c.PauseJob(ref)
+	c.Start()
+
+	// 参考任务暂停期间相对任务照常执行
+	got := stepFires(t, clk, ran, 7)
+	if len(got) != 1 || got[0].name != "rel" || !got[0].at.Equal(testStart.Add(6*time.Second)) {
+		t.Errorf("fires = %v, want only rel at 09:00:01", got)
+	}
+}
+
+func TestRelativeJobUnknownReference(t *testing.T) {
+	c, _ := newFakeCron(t)
+	if id, err := c.AddRelativeJobE(42, time.Second, func() {}); id != -1 || err != ErrNotFound {
+		t.Errorf("id %d, err %v", id, err)
+	}
+}
@@ -0,0 +1,132 @@
+package cron
+
+import (
+	"errors"
+
+	"github.com/robfig/cron/v3"
+)
+
+// ErrCustomSchedule 任务使用的不是 spec 调度（比如 AddFixedDelayJob），不能用 spec 替换
+var ErrCustomSchedule = errors.New("cron: job does not use a spec schedule")
+
+// ReloadJob 用新的 spec 和配置替换任务，id、统计信息和执行记录保持不变
+// 新 spec 解析失败时任务保持原样不做任何修改；正在进行的执行不受影响，新配置从下一次执行开始生效
+// 分组任务会被替换为只有一个 spec 的任务
+func (s *Cron) ReloadJob(id int, spec string, options ...Option) error {
+	opt := applyOptions(options...)
+	return s.reloadStored(id, spec, &opt, nil)
+}
+
+// UpdateJob 同 ReloadJob，同时替换任务函数，id、状态、统计信息和执行记录保持不变
+// 替换在锁内一次完成，并发的定时触发要么使用旧的函数和配置，要么使用新的，不会混用；
+// 正在进行的执行不受影响
+func (s *Cron) UpdateJob(id int, spec string, f func(), options ...Option) error {
+	opt := applyOptions(options...)
+	return s.reloadStored(id, spec, &opt, plain(f))
+}
+
+// RescheduleJob 只替换任务的 spec，任务函数、配置、统计信息和 id 保持不变
+// 其余行为与 ReloadJob 一致：解析失败时原调度保持不变并返回错误，id 不存在返回 ErrNotFound
+func (s *Cron) RescheduleJob(id int, spec string) error {
+	return s.reloadStored(id, spec, nil, nil)
+}
+
+// reloadStored 调用 reload，任务保存在 JobStore 中时同步更新记录，改名时删除原来的记录
+func (s *Cron) reloadStored(id int, spec string, opt *options, raw jobFunc) error {
+	old := s.storedName(id)
+	if err := s.reload(id, spec, opt, raw); err != nil {
+		return err
+	}
+	if err := s.persistJob(id); err != nil {
+		return err
+	}
+	if name := s.storedName(id); old != "" && name != old {
+		s.unpersistJob(old)
+	}
+	return nil
+}
+
+// reload 替换任务的 spec，opt 和 raw 为 nil 时保留原来的配置和任务函数
+func (s *Cron) reload(id int, spec string, opt *options, raw jobFunc) error {
+	if opt != nil {
+		spec = withTimezone(spec, opt.Timezone)
+	} else if _, old, ok := s.loadOptions(id); ok {
+		spec = withTimezone(spec, old.Timezone)
+	}
+	sched, err := s.parse(spec)
+	if err != nil {
+		return err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	e, ok := s.load(id)
+	if !ok {
+		return ErrNotFound
+	}
+	if e.custom() {
+		return ErrCustomSchedule
+	}
+	if opt != nil {
+		// AddStoredJob 的函数名不是用户传入的配置，需要保留
+		opt.Func = e.opt.Func
+		// 保存的任务以任务名为 key，没有指定新名字时保留原来的名字
+		if opt.Func != "" && opt.Name == "" {
+			opt.Name = e.opt.Name
+		}
+		// 任务函数不变时继续使用原来的参数
+		if raw == nil {
+			opt.Payload, opt.PayloadJSON = e.opt.Payload, e.opt.PayloadJSON
+		}
+	}
+	if opt != nil && opt.Name != e.opt.Name {
+		if err := s.checkName(opt.Name); err != nil {
+			return err
+		}
+		if s.names[e.opt.Name] == id {
+			delete(s.names, e.opt.Name)
+		}
+		if opt.Name != "" {
+			s.names[opt.Name] = id
+		}
+	}
+
+	s.replace(e, []string{spec}, []cron.Schedule{sched}, raw, opt)
+
+	return nil
+}
+
+// replace 原地替换任务的调度，raw 和 opt 为 nil 时保留原来的值，调用方需持有写锁
+// id、统计信息和执行记录保持不变，正在进行的执行不受影响
+func (s *Cron) replace(e *entry, specs []string, scheds []cron.Schedule, raw jobFunc, opt *options) {
+	e.unschedule(s.c)
+	e.specs = specs
+	e.scheds = scheds
+	if raw != nil {
+		e.raw = raw
+	}
+	if opt != nil {
+		e.opt = *opt
+		e.history.resize(opt.HistorySize)
+		e.seen.setKey(opt.IdempotencyKey)
+	}
+	if raw != nil || opt != nil {
+		e.f = s.wrap(e.id, e.raw, e.opt)
+	}
+	if !e.paused {
+		e.schedule(s)
+	}
+	s.refreshRelatives(e)
+}
+
+// custom 任务是否使用了 spec 之外的调度，调用方需持有锁
+func (e *entry) custom() bool {
+	for _, sched := range e.scheds {
+		switch sched.(type) {
+		case *cron.SpecSchedule, cron.ConstantDelaySchedule:
+		default:
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,146 @@
+package cron
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// reloadSnapshot 记录 ReloadJob 失败时不应改变的状态
+type reloadSnapshot struct {
+	info    JobInfo
+	timeout time.Duration
+	mode    RunMode
+}
+
+func snapshot(t *testing.T, c *Cron, id int) reloadSnapshot {
+	t.Helper()
+	info, ok := c.jobInfo(id)
+	_, opt, ok2 := c.loadOptions(id)
+	if !ok || !ok2 {
+		t.Fatalf("job %d not found", id)
+	}
+	return reloadSnapshot{info: info, timeout: opt.Timeout, mode: opt.RunMode}
+}
+
+func assertUnchanged(t *testing.T, c *Cron, id int, before reloadSnapshot) {
+	t.Helper()
+	after := snapshot(t, c, id)
+	if after.info.Spec != before.info.Spec || after.info.Name != before.info.Name ||
+		!after.info.Next.Equal(before.info.Next) || after.info.Status != before.info.Status {
+		t.Errorf("job changed: before %+v, after %+v", before.info, after.info)
+	}
+	if after.timeout != before.timeout || after.mode != before.mode {
+		t.Errorf("options changed: timeout %v -> %v, mode %v -> %v",
+			before.timeout, after.timeout, before.mode, after.mode)
+	}
+}
+
+func TestReloadJobInvalidSpecRollsBack(t *testing.T) {
+	c, clk := newFakeCron(t)
+	ran := make(chan time.Time, 4)
+	id := c.AddJob("0 * * * * *", func() { ran <- clk.Now() },
+		WithName("report"), WithTimeout(time.Minute), WithRunMode(ModeJobParallel))
+	before := snapshot(t, c, id)
+
+	for _, spec := range []string{"not a spec", "61 * * * * *", ""} {
+		err := c.ReloadJob(id, spec, WithName("renamed"), WithTimeout(time.Second))
+		if err == nil {
+			t.Fatalf("ReloadJob accepted %q", spec)
+		}
+		assertUnchanged(t, c, id, before)
+	}
+	if got, ok := c.GetJobByName("report"); !ok || got.ID != id {
+		t.Error("old name no longer resolves")
+	}
+	if _, ok := c.GetJobByName("renamed"); ok {
+		t.Error("new name was registered")
+	}
+
+	// 任务仍按原来的 spec 触发
+	c.Start()
+	blockUntil(t, clk, 1)
+	clk.Advance(5 * time.Second)
+	if at := receive(t, ran); !at.Equal(testStart.Add(5 * time.Second)) {
+		t.Fatalf("fired at %v", at)
+	}
+}
+
+func TestUpdateJobInvalidSpecKeepsFunction(t *testing.T) {
+	c, _ := newFakeCron(t)
+	ran := make(chan string, 2)
+	id := c.AddJob("0 0 9 * * *", func() { ran <- "old" })
+	before := snapshot(t, c, id)
+
+	if err := c.UpdateJob(id, "bogus", func() { ran <- "new" }, WithTimeout(time.Second)); err == nil {
+		t.Fatal("UpdateJob accepted an invalid spec")
+	}
+	assertUnchanged(t, c, id, before)
+	c.Call(id)
+	if got := receive(t, ran); got != "old" {
+		t.Errorf("Call ran the %s function", got)
+	}
+}
+
+func TestReloadJobDuplicateNameRollsBack(t *testing.T) {
+	c, _ := newFakeCron(t)
+	c.AddJob("0 0 8 * * *", func() {}, WithName("taken"))
+	id := c.AddJob("0 0 9 * * *", func() {}, WithName("mine"), WithTimeout(time.Minute))
+	before := snapshot(t, c, id)
+
+	if err := c.ReloadJob(id, "0 0 10 * * *", WithName("taken")); !errors.Is(err, ErrDuplicateName) {
+		t.Fatalf("err = %v, want ErrDuplicateName", err)
+	}
+	assertUnchanged(t, c, id, before)
+	if got, _ := c.GetJobByName("mine"); got.ID != id {
+		t.Error("old name no longer resolves")
+	}
+}
+
+func TestReloadJobKeepsPausedJobOnFailure(t *testing.T) {
+	c, _ := newFakeCron(t)
+	id := c.AddJob("0 0 9 * * *", func() {})
+	c.PauseJob(id)
+	before := snapshot(t, c, id)
+
+	if err := c.ReloadJob(id, "bogus"); err == nil {
+		t.Fatal("ReloadJob accepted an invalid spec")
+	}
+	assertUnchanged(t, c, id, before)
+	if c.GetStatus(id) != StatusPaused {
+		t.Error("failed reload resumed the job")
+	}
+}
+
+func TestReloadJobRejectsCustomSchedule(t *testing.T) {
+	c, _ := newFakeCron(t)
+	id := c.AddFixedDelayJob(time.Minute, func() {}, WithTimeout(time.Second))
+	before := snapshot(t, c, id)
+
+	if err := c.ReloadJob(id, "0 0 9 * * *"); !errors.Is(err, ErrCustomSchedule) {
+		t.Fatalf("err = %v, want ErrCustomSchedule", err)
+	}
+	assertUnchanged(t, c, id, before)
+	if err := c.ReloadJob(id+100, "0 0 9 * * *"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("unknown id: err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestReloadJobAppliesValidSpec(t *testing.T) {
+	c, _ := newFakeCron(t)
+	id := c.AddJob("0 0 9 * * *", func() {}, WithName("report"))
+	if err := c.ReloadJob(id, "0 30 10 * * *", WithName("daily"), WithTimeout(time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	info, _ := c.jobInfo(id)
+	_, opt, _ := c.loadOptions(id)
+	if info.Spec != "0 30 10 * * *" || info.Name != "daily" || opt.Timeout != time.Second {
+		t.Errorf("reload not applied: %+v, timeout %v", info, opt.Timeout)
+	}
+	if info.Next.Hour() != 10 || info.Next.Minute() != 30 {
+		t.Errorf("next run = %v", info.Next)
+	}
+	if _, ok := c.GetJobByName("report"); ok {
+		t.Error("old name still resolves")
+	}
+}
@@ -0,0 +1,26 @@
+package cron
+
+import "context"
+
+// AddJobResult 添加有返回值的任务，每次执行后将结果交给 sink
+// Go 的方法不支持类型参数，因此以函数形式提供
+// 返回的 id 与 AddJob 相同，可用于删除、调用等操作，失败返回 -1
+func AddJobResult[T any](s *Cron, spec string, f func() (T, error), sink func(id int, result T, err error), options ...Option) (id int) {
+	id, _ = AddJobResultE(s, spec, f, sink, options...)
+	return id
+}
+
+// AddJobResultE 同 AddJobResult，但会返回失败原因
+func AddJobResultE[T any](s *Cron, spec string, f func() (T, error), sink func(id int, result T, err error), options ...Option) (id int, err error) {
+	s.warnStopped(spec)
+
+	return s.addSpec(spec, func(id int) jobFunc {
+		return func(context.Context) error {
+			result, err := f()
+			if sink != nil {
+				sink(id, result, err)
+			}
+			return err
+		}
+	}, applyOptions(options...))
+}
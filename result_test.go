@@ -0,0 +1,66 @@
+package cron
+
+import (
+	"errors"
+	"testing"
+)
+
+// sinkCall 一次 sink 调用收到的参数
+type sinkCall struct {
+	id  int
+	n   int
+	err error
+}
+
+func TestJobResultGoesToSink(t *testing.T) {
+	c := NewCron(WithLogger(DiscardLogger))
+	var got []sinkCall
+	sink := func(id int, n int, err error) { got = append(got, sinkCall{id, n, err}) }
+	boom := errors.New("boom")
+	calls := 0
+	id := AddJobResult(c, "0 0 9 * * *", func() (int, error) {
+		calls++
+		if calls == 2 {
+			return 0, boom
+		}
+		return calls * 10, nil
+	}, sink)
+	c.Call(id)
+	c.Call(id)
+
+	if len(got) != 2 || got[0] != (sinkCall{id, 10, nil}) || got[1].id != id || got[1].err != boom {
+		t.Fatalf("sink got %+v", got)
+	}
+	// 返回的错误计入失败次数
+	if st, _ := c.Stats(id); st.Successes != 1 || st.Failures != 1 {
+		t.Errorf("stats = %+v", st)
+	}
+}
+
+func TestJobResultEachRetry(t *testing.T) {
+	c := NewCron(WithLogger(DiscardLogger))
+	var got []sinkCall
+	id := AddJobResult(c, "0 0 9 * * *", func() (int, error) {
+		return len(got), errors.New("boom")
+	}, func(id int, n int, err error) { got = append(got, sinkCall{id, n, err}) }, WithRetry(2, 0))
+	c.Call(id)
+	if len(got) != 3 || got[2].n != 2 {
+		t.Errorf("sink got %+v, want one result per attempt", got)
+	}
+}
+
+func TestJobResultNilSink(t *testing.T) {
+	c := NewCron(WithLogger(DiscardLogger))
+	ran := false
+	id := AddJobResult[string](c, "0 0 9 * * *", func() (string, error) {
+		ran = true
+		return "ok", nil
+	}, nil)
+	c.Call(id)
+	if !ran {
+		t.Error("job did not run")
+	}
+	if id, err := AddJobResultE(c, "bogus", func() (int, error) { return 0, nil }, nil); id != -1 || err == nil {
+		t.Errorf("invalid spec: id %d, err %v", id, err)
+	}
+}
@@ -0,0 +1,172 @@
+package cron
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// BackoffStrategy 决定每次重试前等待多久，见 WithRetryPolicy
+type BackoffStrategy interface {
+	// Backoff 返回第 attempt 次失败后、下一次重试前的等待时间，attempt 从 1 开始
+	Backoff(attempt int) time.Duration
+}
+
+// ConstantBackoff 每次重试前等待相同的时间
+type ConstantBackoff time.Duration
+
+func (b ConstantBackoff) Backoff(int) time.Duration {
+	return time.Duration(b)
+}
+
+// ExponentialBackoff 等待时间从 Base 开始每次翻倍，最多不超过 Max，Max 为 0 时不设上限
+type ExponentialBackoff struct {
+	Base time.Duration
+	Max  time.Duration
+}
+
+func (b ExponentialBackoff) Backoff(attempt int) time.Duration {
+	d := b.Base
+	for i := 1; i < attempt; i++ {
+		if (b.Max > 0 && d >= b.Max) || d > math.MaxInt64/2 {
+			break
+		}
+		d *= 2
+	}
+	if b.Max > 0 && d > b.Max {
+		d = b.Max
+	}
+	return d
+}
+
+type _Retry struct {
+	max     int
+	backoff BackoffStrategy
+}
+
+func (r _Retry) apply(opts *options) {
+	opts.RetryMax = r.max
+	opts.RetryBackoff = r.backoff
+}
+
+// WithRetry 任务返回错误或 panic 时最多重试 maxRetries 次，每次重试前等待 backoff
+// 等同于 WithRetryPolicy(maxRetries, ConstantBackoff(backoff))，每次执行最多尝试 maxRetries+1 次
+func WithRetry(maxRetries int, backoff time.Duration) Option {
+	return WithRetryPolicy(maxRetries, ConstantBackoff(backoff))
+}
+
+// WithRetryPolicy 任务返回错误或 panic 时最多重试 maxRetries 次，等待时间由 backoff 决定，nil 表示不等待
+// 第一次执行不算重试，maxRetries 为 0 时只执行一次；最后一次尝试仍然 panic 时按 WithRecover 的设置处理
+// 重试发生在同一次执行之内，ModeJobSerial 下重试期间任务保持 StatusRunning，下一次触发会被跳过；
+// 等待期间调度器 Stop 时不再重试
+func WithRetryPolicy(maxRetries int, backoff BackoffStrategy) Option {
+	return _Retry{max: maxRetries, backoff: backoff}
+}
+
+type _ErrorHandler func(id int, attempt int, err error)
+
+func (f _ErrorHandler) apply(opts *options) {
+	opts.ErrorHandler = f
+}
+
+// WithErrorHandler 设置任务最终失败时的回调，attempt 为一共尝试的次数，err 为最后一次的错误
+// 未设置时交给 Logger
+func WithErrorHandler(f func(id int, attempt int, err error)) Option {
+	return _ErrorHandler(f)
+}
+
+type _OnError func(id int, err error)
+
+func (f _OnError) apply(opts *options) {
+	opts.OnError = f
+}
+
+// WithOnError 设置执行失败时的回调，每次以错误结束的执行调用一次，重试全部失败后才算失败
+// err 为任务返回的错误，panic 时为 *PanicError，超时时为 ErrTimeout；
+// 与 WithErrorHandler 不同，设置它不会影响默认的日志
+func WithOnError(f func(id int, err error)) Option {
+	return _OnError(f)
+}
+
+// AddJobE2 添加返回错误的任务，失败时按 WithRetry 重试，最终失败交给 WithErrorHandler
+// 返回的 id 与 AddJob 相同，失败返回 -1
+func (s *Cron) AddJobE2(spec string, f func() error, options ...Option) (id int) {
+	s.warnStopped(spec)
+
+	id, _ = s.addSpec(spec, func(int) jobFunc {
+		return func(context.Context) error { return f() }
+	}, applyOptions(options...))
+
+	return id
+}
+
+// retry 包装任务函数，失败时按 opt 重试，最终失败时报告最后一次的错误
+func (s *Cron) retry(id int, f jobFunc, opt options) jobFunc {
+	return func(ctx context.Context) error {
+		var err error
+		attempt := 0
+		for {
+			attempt++
+			last := attempt > opt.RetryMax
+			if err = s.try(ctx, id, f, opt, attempt, last); err == nil {
+				return nil
+			}
+			if last || !s.backoff(opt, attempt) {
+				break
+			}
+		}
+
+		s.fail(id, opt, attempt, err)
+		return err
+	}
+}
+
+// try 执行一次 f 并写入审计记录，last 为 false 时将 panic 转换为 *PanicError 以便重试
+func (s *Cron) try(ctx context.Context, id int, f jobFunc, opt options, attempt int, last bool) (err error) {
+	start := time.Now()
+	defer func() {
+		r := recover()
+		if r != nil && !last {
+			err, r = &PanicError{Value: r}, nil
+		}
+		s.audit(ctx, id, opt, attempt, start, err, r)
+		if r != nil {
+			panic(r)
+		}
+	}()
+	return f(ctx)
+}
+
+// backoff 重试前等待，等待期间调度器 Stop 时返回 false
+func (s *Cron) backoff(opt options, attempt int) bool {
+	if opt.RetryBackoff == nil {
+		return true
+	}
+	d := opt.RetryBackoff.Backoff(attempt)
+	if d <= 0 {
+		return true
+	}
+
+	s.lock.RLock()
+	done := s.root.Done()
+	s.lock.RUnlock()
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-done:
+		return false
+	}
+}
+
+// fail 报告任务最终失败
+func (s *Cron) fail(id int, opt options, attempt int, err error) {
+	if opt.ErrorHandler != nil {
+		opt.ErrorHandler(id, attempt, err)
+		return
+	}
+	l, kv := s.jobLogger(id)
+	l.Error(err, "job failed", append(kv, "attempt", attempt)...)
+}
@@ -0,0 +1,152 @@
+package cron
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// backoffRecorder 记录每次调用 Backoff 时的 attempt
+type backoffRecorder struct {
+	mu       sync.Mutex
+	attempts []int
+}
+
+func (b *backoffRecorder) Backoff(attempt int) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.attempts = append(b.attempts, attempt)
+	return time.Millisecond
+}
+
+func (b *backoffRecorder) get() []int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]int(nil), b.attempts...)
+}
+
+func TestRetryAttemptCount(t *testing.T) {
+	boom := errors.New("boom")
+	for _, retries := range []int{0, 1, 3} {
+		c := NewCron(WithLogger(DiscardLogger))
+		calls := 0
+		var failed []int
+		backoff := &backoffRecorder{}
+		id := c.AddJobE2("0 0 9 * * *", func() error {
+			calls++
+			return boom
+		}, WithRetryPolicy(retries, backoff), WithErrorHandler(func(_ int, attempt int, err error) {
+			if err != boom {
+				t.Errorf("final error %v", err)
+			}
+			failed = append(failed, attempt)
+		}))
+		c.Call(id)
+
+		// 第一次执行加上 retries 次重试，每次重试前等待一次
+		if calls != retries+1 {
+			t.Errorf("WithRetryPolicy(%d): %d calls, want %d", retries, calls, retries+1)
+		}
+		if !reflect.DeepEqual(failed, []int{retries + 1}) {
+			t.Errorf("WithRetryPolicy(%d): error handler got %v", retries, failed)
+		}
+		var want []int
+		for i := 1; i <= retries; i++ {
+			want = append(want, i)
+		}
+		if got := backoff.get(); !reflect.DeepEqual(got, want) {
+			t.Errorf("WithRetryPolicy(%d): backoff attempts %v, want %v", retries, got, want)
+		}
+	}
+}
+
+func TestRetryStopsOnSuccess(t *testing.T) {
+	c := NewCron(WithLogger(DiscardLogger))
+	calls := 0
+	failed := false
+	id := c.AddJobE2("0 0 9 * * *", func() error {
+		calls++
+		if calls < 2 {
+			return errors.New("boom")
+		}
+		return nil
+	}, WithRetry(5, 0), WithErrorHandler(func(int, int, error) { failed = true }))
+	c.Call(id)
+	if calls != 2 || failed {
+		t.Errorf("%d calls, failed %v, want 2 calls and success", calls, failed)
+	}
+	if st, _ := c.Stats(id); st.Successes != 1 || st.Failures != 0 {
+		t.Errorf("stats = %+v", st)
+	}
+}
+
+func TestRetryPanics(t *testing.T) {
+	c := NewCron(WithLogger(DiscardLogger))
+	calls := 0
+	var recovered []interface{}
+	id := c.AddJob("0 0 9 * * *", func() {
+		calls++
+		panic("boom")
+	}, WithRetry(2, 0), WithPanicHandler(func(_ int, r interface{}, _ []byte) { recovered = append(recovered, r) }))
+	c.Call(id)
+	// 前两次的 panic 触发重试，最后一次交给 panic 处理
+	if calls != 3 || len(recovered) != 1 || recovered[0] != "boom" {
+		t.Errorf("%d calls, recovered %v, want 3 calls and one panic", calls, recovered)
+	}
+}
+
+func TestRetryBackoffWaits(t *testing.T) {
+	c := NewCron(WithLogger(DiscardLogger))
+	var at []time.Time
+	id := c.AddJobE2("0 0 9 * * *", func() error {
+		at = append(at, time.Now())
+		return errors.New("boom")
+	}, WithRetry(2, 20*time.Millisecond))
+	c.Call(id)
+	if len(at) != 3 {
+		t.Fatalf("%d calls, want 3", len(at))
+	}
+	for i := 1; i < len(at); i++ {
+		if d := at[i].Sub(at[i-1]); d < 20*time.Millisecond {
+			t.Errorf("retry %d after %v, want at least 20ms", i, d)
+		}
+	}
+}
+
+func TestRetryStopsWithScheduler(t *testing.T) {
+	c := NewCron(WithLogger(DiscardLogger))
+	c.Start()
+	first := make(chan struct{}, 2)
+	failed := make(chan int, 1)
+	id := c.AddJobE2("0 0 9 * * *", func() error {
+		first <- struct{}{}
+		return errors.New("boom")
+	}, WithRetry(3, time.Hour), WithErrorHandler(func(_ int, attempt int, _ error) { failed <- attempt }))
+	c.CallAsync(id)
+	receive(t, first)
+
+	// 等待重试期间 Stop，不再重试
+	c.Stop()
+	if attempt := receive(t, failed); attempt != 1 {
+		t.Errorf("failed after %d attempts, want 1", attempt)
+	}
+	never(t, first)
+}
+
+func TestExponentialBackoff(t *testing.T) {
+	b := ExponentialBackoff{Base: time.Second, Max: 10 * time.Second}
+	want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 10 * time.Second, 10 * time.Second}
+	for i, w := range want {
+		if got := b.Backoff(i + 1); got != w {
+			t.Errorf("Backoff(%d) = %v, want %v", i+1, got, w)
+		}
+	}
+	if got := (ExponentialBackoff{Base: time.Hour}).Backoff(200); got <= 0 {
+		t.Errorf("unbounded backoff overflowed to %v", got)
+	}
+	if got := ConstantBackoff(time.Second).Backoff(7); got != time.Second {
+		t.Errorf("ConstantBackoff = %v", got)
+	}
+}
@@ -0,0 +1,61 @@
+package cron
+
+import (
+	"github.com/robfig/cron/v3"
+)
+
+type _CronOptions []cron.Option
+
+func (o _CronOptions) applyCron(opts *cronOptions) {
+	opts.CronOptions = append(opts.CronOptions, o...)
+}
+
+// WithCronOptions 创建底层 robfig/cron 时追加的配置，比如 cron.WithChain、cron.WithLogger，
+// 在本包的默认配置之后生效，方便从直接使用 robfig/cron 迁移
+// spec 由本包解析，这里传入的 cron.WithParser 对任务不生效，请使用 WithParser；时区请使用 WithLocation
+func WithCronOptions(opts ...cron.Option) CronOption {
+	return _CronOptions(opts)
+}
+
+type _Parser struct {
+	cron.ScheduleParser
+}
+
+func (p _Parser) applyCron(opts *cronOptions) {
+	opts.Parser = p.ScheduleParser
+}
+
+// WithParser 使用自定义的 spec 解析器，比如 cron.NewParser 生成的、支持可选秒字段的解析器
+// 设置后不再检查 spec 的字段数，SpecError 不再给出出错的字段；
+// AddSecondJob 等辅助方法仍按 WithoutSeconds 的设置生成 spec，需要解析器支持对应的格式
+func WithParser(p cron.ScheduleParser) CronOption {
+	return _Parser{p}
+}
+
+// Entry 返回任务在底层 robfig/cron 中的 Entry，分组任务返回第一个 spec 对应的 Entry
+// 任务不存在、已暂停或调度器尚未注册时返回 false；返回的是快照，修改它不会影响调度
+func (s *Cron) Entry(id int) (cron.Entry, bool) {
+	ids := s.EntryIDs(id)
+	if len(ids) == 0 {
+		return cron.Entry{}, false
+	}
+	entry := s.c.Entry(ids[0])
+	if s.driver != nil {
+		s.lock.RLock()
+		entry.Next, entry.Prev = s.entryTimes(ids[0])
+		s.lock.RUnlock()
+	}
+	return entry, entry.Valid()
+}
+
+// EntryIDs 返回任务在底层 robfig/cron 中的 EntryID，每个 spec 对应一个
+// 暂停、ReloadJob 等操作会重新注册，之前返回的 EntryID 随之失效
+func (s *Cron) EntryIDs(id int) []cron.EntryID {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	e, ok := s.load(id)
+	if !ok {
+		return nil
+	}
+	return append([]cron.EntryID(nil), e.ids...)
+}
@@ -0,0 +1,43 @@
+package cron
+
+import "sync"
+
+// running 统计进行中的执行次数，Stop 用它等待所有执行结束
+// 与 sync.WaitGroup 不同，计数归零后可以继续 add，适合 Stop 与手动调用并发的场景
+type running struct {
+	mu   sync.Mutex
+	n    int
+	idle []chan struct{}
+}
+
+func (r *running) add() {
+	r.mu.Lock()
+	r.n++
+	r.mu.Unlock()
+}
+
+func (r *running) done() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.n--
+	if r.n > 0 {
+		return
+	}
+	for _, ch := range r.idle {
+		close(ch)
+	}
+	r.idle = nil
+}
+
+// wait 返回的 chan 会在计数归零时关闭，当前没有执行时立即关闭
+func (r *running) wait() <-chan struct{} {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	ch := make(chan struct{})
+	if r.n == 0 {
+		close(ch)
+		return ch
+	}
+	r.idle = append(r.idle, ch)
+	return ch
+}
@@ -0,0 +1,212 @@
+package cron
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// onceSchedule 只触发一次的调度
+// 首次计算总是返回 at（已过期则马上触发），之后只在 at 之前返回 at，
+// 避免 robfig 触发后重新计算 Next 时再次触发
+type onceSchedule struct {
+	mu    sync.Mutex
+	at    time.Time
+	asked bool
+	done  bool
+	// then 每次触发结束后调用，无论任务执行、被跳过还是出错，planned 为计划时间，见 settle
+	then func(planned time.Time)
+}
+
+func (o *onceSchedule) Next(t time.Time) time.Time {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.done {
+		return time.Time{}
+	}
+	if !o.asked || t.Before(o.at) {
+		o.asked = true
+		return o.at
+	}
+	return time.Time{}
+}
+
+// fire 标记已经触发
+func (o *onceSchedule) fire() {
+	o.mu.Lock()
+	o.done = true
+	o.mu.Unlock()
+}
+
+// pending 是否尚未触发
+func (o *onceSchedule) pending() bool {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return !o.done
+}
+
+// rewind 让尚未触发的调度在重新 Start 时能被再次计算
+func (o *onceSchedule) rewind() {
+	o.mu.Lock()
+	o.asked = false
+	o.mu.Unlock()
+}
+
+// rewind 重新 Start 之前调用，robfig 会在 Start 时重新计算所有 Next
+func (s *Cron) rewind() {
+	s.entry.Range(func(_, value interface{}) bool {
+		for _, sched := range value.(*entry).scheds {
+			if o, ok := sched.(*onceSchedule); ok {
+				o.rewind()
+			}
+		}
+		return true
+	})
+}
+
+// settle 一次触发结束后调用，标记任务当前的 onceSchedule 已触发并执行它的 then
+// 固定延迟任务在这里安排下一次执行，放在任务函数之外，
+// 被互斥组、分布式锁、限流等跳过的触发同样会安排下一次，任务不会就此停止
+func (s *Cron) settle(id int) {
+	s.lock.RLock()
+	e, ok := s.load(id)
+	var o *onceSchedule
+	if ok && len(e.scheds) == 1 {
+		o, _ = e.scheds[0].(*onceSchedule)
+	}
+	s.lock.RUnlock()
+	if o == nil || o.then == nil {
+		return
+	}
+	o.fire()
+	o.then(o.at)
+}
+
+// reschedule 用新的调度替换任务当前的调度，id 保持不变
+func (s *Cron) reschedule(id int, sched cron.Schedule) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	entryI, ok := s.entry.Load(id)
+	if !ok {
+		return
+	}
+	e := entryI.(*entry)
+	e.unschedule(s.c)
+	e.scheds = []cron.Schedule{sched}
+	if !e.paused {
+		e.schedule(s)
+	}
+	s.refreshRelatives(e)
+}
+
+// schedule 将所有调度注册到 robfig，调用方需持有写锁
+func (e *entry) schedule(s *Cron) {
+	id := e.id
+	for _, sched := range e.scheds {
+		e.ids = append(e.ids, s.c.Schedule(sched, cron.FuncJob(func() { s.execute(id, trigger{source: SourceSchedule, at: time.Now()}) })))
+	}
+	if s.driver != nil {
+		s.driver.notify()
+	}
+}
+
+// unschedule 从 robfig 中移除所有调度，调用方需持有写锁
+func (e *entry) unschedule(c *cron.Cron) {
+	for _, entryId := range e.ids {
+		c.Remove(entryId)
+	}
+	e.ids = nil
+}
+
+// peek 不改变状态地计算 t 之后的下一次触发时间
+func (o *onceSchedule) peek(t time.Time) time.Time {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.done || !t.Before(o.at) {
+		return time.Time{}
+	}
+	return o.at
+}
+
+// peekNext 计算 t 之后的下一次触发时间，不修改调度本身的状态
+func peekNext(sched cron.Schedule, t time.Time) time.Time {
+	if o, ok := sched.(*onceSchedule); ok {
+		return o.peek(t)
+	}
+	return sched.Next(t)
+}
+
+// anchoredSchedule 以 anchor 为相位、每隔 every 触发一次的调度
+type anchoredSchedule struct {
+	anchor time.Time
+	every  time.Duration
+}
+
+func (a anchoredSchedule) Next(t time.Time) time.Time {
+	if t.Before(a.anchor) {
+		return a.anchor.Add(a.every)
+	}
+	n := t.Sub(a.anchor)/a.every + 1
+	return a.anchor.Add(n * a.every)
+}
+
+// AddEveryFromNowJob 添加从注册时刻开始计算相位的间隔任务
+// 第一次在注册后 d 执行，之后每隔 d 执行一次；
+// robfig 的 @every 从调度器启动时算起，与注册时刻不完全一致
+// d 必须大于 0，否则返回 -1
+func (s *Cron) AddEveryFromNowJob(d time.Duration, f func(), options ...Option) (id int) {
+	id, _ = s.AddEveryFromNowJobE(d, f, options...)
+	return id
+}
+
+// AddEveryFromNowJobE 同 AddEveryFromNowJob，但会返回失败原因，d 不大于 0 返回 ErrInvalidInterval
+func (s *Cron) AddEveryFromNowJobE(d time.Duration, f func(), options ...Option) (id int, err error) {
+	if d <= 0 {
+		return -1, ErrInvalidInterval
+	}
+	spec := fmt.Sprintf("@every-from-now %v", d)
+	s.warnStopped(spec)
+
+	id = s.genID()
+	sched := anchoredSchedule{anchor: s.clock.Now(), every: d}
+	if err = s.addEntry(id, []string{spec}, []cron.Schedule{sched}, plain(f), applyOptions(options...)); err != nil {
+		return -1, err
+	}
+
+	return id, nil
+}
+
+// AddIntervalJob 添加每隔 d 执行一次的任务，使用 robfig 的 @every，间隔不受分钟、小时等进位影响
+// 与 AddSecondJob 等辅助方法生成的 */N 不同，d 不需要整除 60 或 24，比如 7 秒、90 分钟
+// d 会被截断到秒，小于 1 秒时按 1 秒处理
+// 开启 WithRandom 时第一次执行在 [0, d) 之间随机延后，之后仍然每隔 d 执行，
+// 让同时注册的多个任务错开执行
+func (s *Cron) AddIntervalJob(d time.Duration, f func(), options ...Option) (id int) {
+	id, _ = s.AddIntervalJobE(d, f, options...)
+	return id
+}
+
+// AddIntervalJobE 同 AddIntervalJob，但会返回失败原因，d 不大于 0 返回 ErrInvalidInterval
+func (s *Cron) AddIntervalJobE(d time.Duration, f func(), options ...Option) (id int, err error) {
+	if d <= 0 {
+		return -1, ErrInvalidInterval
+	}
+	spec := fmt.Sprintf("@every %v", d)
+	opt := applyOptions(options...)
+	if !opt.Random {
+		return s.AddJobE(spec, f, options...)
+	}
+	s.warnStopped(spec)
+
+	every := cron.Every(d).Delay
+	offset := time.Duration(s.rand.Int63n(int64(every)))
+	id = s.genID()
+	sched := anchoredSchedule{anchor: s.clock.Now().Add(offset - every), every: every}
+	if err = s.addEntry(id, []string{spec}, []cron.Schedule{sched}, plain(f), opt); err != nil {
+		return -1, err
+	}
+
+	return id, nil
+}
@@ -0,0 +1,63 @@
+package cron
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEveryFromNowFirstFire(t *testing.T) {
+	c, clk := newFakeCron(t)
+	c.Start()
+
+	// 在不整秒的时刻注册，相位从注册时刻算起，而不是从启动时刻或整秒算起
+	clk.Advance(2500 * time.Millisecond)
+	registered := clk.Now()
+	ran := make(chan time.Time, 4)
+	id := c.AddEveryFromNowJob(7*time.Second, func() { ran <- clk.Now() })
+	if id < 0 {
+		t.Fatal("AddEveryFromNowJob rejected a positive interval")
+	}
+	if next, _ := c.NextRun(id); !next.Equal(registered.Add(7 * time.Second)) {
+		t.Fatalf("NextRun = %v, want %v", next, registered.Add(7*time.Second))
+	}
+
+	blockUntil(t, clk, 1)
+	clk.Advance(7*time.Second - time.Millisecond)
+	never(t, ran)
+	blockUntil(t, clk, 1)
+	clk.Advance(time.Millisecond)
+	if at := receive(t, ran); !at.Equal(registered.Add(7 * time.Second)) {
+		t.Fatalf("first fire at %v, want %v", at, registered.Add(7*time.Second))
+	}
+
+	blockUntil(t, clk, 1)
+	clk.Advance(7 * time.Second)
+	if at := receive(t, ran); !at.Equal(registered.Add(14 * time.Second)) {
+		t.Fatalf("second fire at %v, want %v", at, registered.Add(14*time.Second))
+	}
+}
+
+func TestEveryFromNowAnchorsBeforeStart(t *testing.T) {
+	c, clk := newFakeCron(t)
+	registered := clk.Now()
+	ran := make(chan time.Time, 4)
+	c.AddEveryFromNowJob(5*time.Second, func() { ran <- clk.Now() })
+
+	// 注册后 3 秒才启动，第一次执行仍在注册后 5 秒
+	clk.Advance(3 * time.Second)
+	c.Start()
+	blockUntil(t, clk, 1)
+	clk.Advance(2 * time.Second)
+	if at := receive(t, ran); !at.Equal(registered.Add(5 * time.Second)) {
+		t.Fatalf("first fire at %v, want %v", at, registered.Add(5*time.Second))
+	}
+}
+
+func TestEveryFromNowRejectsNonPositive(t *testing.T) {
+	c, _ := newFakeCron(t)
+	for _, d := range []time.Duration{0, -time.Second} {
+		if id, err := c.AddEveryFromNowJobE(d, func() {}); id != -1 || err != ErrInvalidInterval {
+			t.Errorf("d = %v: id %d, err %v", d, id, err)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package cron
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/robfig/cron/v3"
+)
+
+// minuteParser 标准的五段式 crontab 解析器
+var minuteParser = cron.NewParser(
+	cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor,
+)
+
+type _WithoutSeconds struct{}
+
+func (_WithoutSeconds) applyCron(opts *cronOptions) {
+	opts.WithoutSeconds = true
+}
+
+// WithoutSeconds 使用标准的五段式 crontab spec（分 时 日 月 周），不再有秒字段
+// 切换后同一个 spec 的含义会改变："0 9 * * 1" 表示每周一 9 点，六段式的 spec 会解析失败；
+// AddMinuteJob 等辅助方法会生成五段式 spec，随机选择的秒会被忽略，AddSecondJob 总是返回 -1
+func WithoutSeconds() CronOption {
+	return _WithoutSeconds{}
+}
+
+// fieldCount 当前模式下 spec 的字段数
+func (s *Cron) fieldCount() int {
+	if s.seconds {
+		return 6
+	}
+	return 5
+}
+
+// checkFieldCount 检查 spec 的字段数是否与当前模式一致，@every 等描述符不检查
+func (s *Cron) checkFieldCount(spec string) error {
+	_, fields := splitTZ(spec)
+	if s.custom || len(fields) == 0 || strings.HasPrefix(fields[0], "@") {
+		return nil
+	}
+	if n := s.fieldCount(); len(fields) != n {
+		if s.seconds {
+			return fmt.Errorf("expected %d fields (second minute hour day-of-month month day-of-week), found %d", n, len(fields))
+		}
+		return fmt.Errorf("expected %d fields (minute hour day-of-month month day-of-week), found %d; seconds are disabled by WithoutSeconds", n, len(fields))
+	}
+	return nil
+}
+
+// adapt 将辅助方法生成的六段式 spec 转换为当前模式的格式
+func (s *Cron) adapt(spec string) string {
+	if s.seconds || spec == "" {
+		return spec
+	}
+	fields := strings.Fields(spec)
+	return strings.Join(fields[1:], " ")
+}
+
+// expand 将当前模式的 spec 补全为六段式，用于检查和描述
+func (s *Cron) expand(spec string) string {
+	tz, fields := splitTZ(spec)
+	if s.seconds || len(fields) != 5 {
+		return spec
+	}
+	if tz != "" {
+		return tz + " 0 " + strings.Join(fields, " ")
+	}
+	return "0 " + spec
+}
+
+// splitTZ 拆出 spec 开头的时区，与 robfig 一致，允许以 TZ= 或 CRON_TZ= 开头
+func splitTZ(spec string) (tz string, fields []string) {
+	fields = strings.Fields(spec)
+	if len(fields) > 0 && (strings.HasPrefix(fields[0], "TZ=") || strings.HasPrefix(fields[0], "CRON_TZ=")) {
+		return fields[0], fields[1:]
+	}
+	return "", fields
+}
+
+// badField 逐个字段单独解析，返回第一个无法解析的字段名，无法确定时返回空字符串
+func (s *Cron) badField(spec string) string {
+	tz, fields := splitTZ(spec)
+	if s.custom || len(fields) == 0 {
+		return ""
+	}
+	if strings.HasPrefix(fields[0], "@") {
+		return "descriptor"
+	}
+	if len(fields) != s.fieldCount() {
+		return ""
+	}
+	names := specFields
+	if !s.seconds {
+		names = names[1:]
+	}
+
+	probe := func(i int) error {
+		parts := make([]string, 0, len(fields)+1)
+		if tz != "" {
+			parts = append(parts, tz)
+		}
+		for j := range fields {
+			if j == i {
+				parts = append(parts, fields[j])
+			} else {
+				parts = append(parts, "*")
+			}
+		}
+		_, err := s.parser.Parse(strings.Join(parts, " "))
+		return err
+	}
+	if probe(-1) != nil {
+		return "timezone"
+	}
+	for i := range fields {
+		if probe(i) != nil {
+			return names[i].name
+		}
+	}
+	return ""
+}
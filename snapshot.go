@@ -0,0 +1,160 @@
+package cron
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+	"time"
+)
+
+// Snapshot 调度器在某一时刻的状态，可以直接序列化为 JSON，见 Cron.Snapshot
+type Snapshot struct {
+	// Time 生成快照的时间
+	Time time.Time `json:"time"`
+	// Running 调度器是否在运行
+	Running bool `json:"running"`
+	// Jobs 所有任务，按 id 排序
+	Jobs []JobSnapshot `json:"jobs"`
+}
+
+// JobSnapshot 单个任务的状态和计数，字段含义见 JobInfo 和 JobStats
+type JobSnapshot struct {
+	ID           int
+	Name         string
+	Group        string
+	Status       uint
+	Specs        []string
+	Next         time.Time
+	Prev         time.Time
+	AddedAt      time.Time
+	LastRun      time.Time
+	LastDuration time.Duration
+	LastError    error
+	Runs         uint64
+	Successes    uint64
+	Failures     uint64
+	Skipped      uint64
+	TimedOut     uint64
+	Abandoned    uint64
+	Rejected     uint64
+}
+
+// MarshalJSON 状态输出为 ready、running、paused，耗时输出为 1.5s 这样的字符串，错误输出为错误信息
+func (j JobSnapshot) MarshalJSON() ([]byte, error) {
+	out := struct {
+		ID           int       `json:"id"`
+		Name         string    `json:"name,omitempty"`
+		Group        string    `json:"group,omitempty"`
+		Status       string    `json:"status"`
+		Specs        []string  `json:"specs"`
+		Next         time.Time `json:"next"`
+		Prev         time.Time `json:"prev"`
+		AddedAt      time.Time `json:"added_at"`
+		LastRun      time.Time `json:"last_run"`
+		LastDuration string    `json:"last_duration,omitempty"`
+		LastError    string    `json:"last_error,omitempty"`
+		Runs         uint64    `json:"runs"`
+		Successes    uint64    `json:"successes"`
+		Failures     uint64    `json:"failures"`
+		Skipped      uint64    `json:"skipped"`
+		TimedOut     uint64    `json:"timed_out"`
+		Abandoned    uint64    `json:"abandoned"`
+		Rejected     uint64    `json:"rejected"`
+	}{
+		ID:        j.ID,
+		Name:      j.Name,
+		Group:     j.Group,
+		Status:    statusName(j.Status),
+		Specs:     j.Specs,
+		Next:      j.Next,
+		Prev:      j.Prev,
+		AddedAt:   j.AddedAt,
+		LastRun:   j.LastRun,
+		Runs:      j.Runs,
+		Successes: j.Successes,
+		Failures:  j.Failures,
+		Skipped:   j.Skipped,
+		TimedOut:  j.TimedOut,
+		Abandoned: j.Abandoned,
+		Rejected:  j.Rejected,
+	}
+	if !j.LastRun.IsZero() {
+		out.LastDuration = j.LastDuration.String()
+	}
+	if j.LastError != nil {
+		out.LastError = j.LastError.Error()
+	}
+	return json.Marshal(out)
+}
+
+// Snapshot 在同一把读锁下生成所有任务的状态快照
+func (s *Cron) Snapshot() Snapshot {
+	snap := Snapshot{Time: time.Now(), Running: s.IsRunning(), Jobs: []JobSnapshot{}}
+	s.lock.RLock()
+	s.entry.Range(func(key, value interface{}) bool {
+		e := value.(*entry)
+		info, st := e.info(s), e.stats(key.(int))
+		snap.Jobs = append(snap.Jobs, JobSnapshot{
+			ID:           st.ID,
+			Name:         info.Name,
+			Group:        st.Group,
+			Status:       st.Status,
+			Specs:        st.Specs,
+			Next:         info.Next,
+			Prev:         info.Prev,
+			AddedAt:      st.AddedAt,
+			LastRun:      st.LastRun,
+			LastDuration: st.LastDuration,
+			LastError:    st.LastError,
+			Runs:         st.Runs,
+			Successes:    st.Successes,
+			Failures:     st.Failures,
+			Skipped:      st.Skipped,
+			TimedOut:     st.TimedOut,
+			Abandoned:    st.Abandoned,
+			Rejected:     st.Rejected,
+		})
+		return true
+	})
+	s.lock.RUnlock()
+	sort.Slice(snap.Jobs, func(i, j int) bool {
+		return snap.Jobs[i].ID < snap.Jobs[j].ID
+	})
+	return snap
+}
+
+type _SnapshotWriter struct {
+	w        io.Writer
+	interval time.Duration
+}
+
+func (w _SnapshotWriter) applyCron(opts *cronOptions) {
+	opts.SnapshotWriter = w.w
+	opts.SnapshotInterval = w.interval
+}
+
+// WithSnapshotWriter 调度器运行期间每隔 interval 将 Snapshot 以一行 JSON 写入 w，interval 不大于 0 时为 1 分钟
+// 写入在单独的 goroutine 中进行，w 不需要并发安全；写入失败会记录到日志
+func WithSnapshotWriter(w io.Writer, interval time.Duration) CronOption {
+	return _SnapshotWriter{w: w, interval: interval}
+}
+
+// writeSnapshots 定期写入快照，done 关闭时退出
+func (s *Cron) writeSnapshots(done <-chan struct{}) {
+	ticker := time.NewTicker(s.snapshotInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-done:
+			return
+		}
+		data, err := json.Marshal(s.Snapshot())
+		if err == nil {
+			_, err = s.snapshotWriter.Write(append(data, '\n'))
+		}
+		if err != nil {
+			s.logger.Error(err, "write snapshot failed")
+		}
+	}
+}
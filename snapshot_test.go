@@ -0,0 +1,133 @@
+package cron
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSnapshot(t *testing.T) {
+	c := NewCron(WithLogger(DiscardLogger))
+	failing := c.AddJobE2("0 0 9 * * *", func() error { return errors.New("boom") }, WithName("report"), WithGroup("daily"))
+	paused := c.AddJob("0 30 10 * * *", func() {})
+	c.PauseJob(paused)
+	c.Call(failing)
+
+	snap := c.Snapshot()
+	if snap.Running || snap.Time.IsZero() || len(snap.Jobs) != 2 {
+		t.Fatalf("snapshot = %+v", snap)
+	}
+	a, b := snap.Jobs[0], snap.Jobs[1]
+	if a.ID != failing || a.Name != "report" || a.Group != "daily" || a.Status != StatusReady ||
+		a.Runs != 1 || a.Failures != 1 || a.LastError == nil || a.LastRun.IsZero() || a.Next.IsZero() {
+		t.Errorf("failing job = %+v", a)
+	}
+	if b.ID != paused || b.Status != StatusPaused || b.Runs != 0 || len(b.Specs) != 1 || b.Specs[0] != "0 30 10 * * *" {
+		t.Errorf("paused job = %+v", b)
+	}
+
+	c.Start()
+	defer c.Stop()
+	if !c.Snapshot().Running {
+		t.Error("Running = false after Start")
+	}
+}
+
+func TestSnapshotJSON(t *testing.T) {
+	c := NewCron(WithLogger(DiscardLogger))
+	id := c.AddJobE2("0 0 9 * * *", func() error { return errors.New("boom") }, WithName("report"))
+	idle := c.AddJob("0 0 9 * * *", func() {})
+	c.PauseJob(idle)
+	c.Call(id)
+
+	data, err := json.Marshal(c.Snapshot())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out struct {
+		Running bool                     `json:"running"`
+		Jobs    []map[string]interface{} `json:"jobs"`
+	}
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatal(err)
+	}
+	if len(out.Jobs) != 2 {
+		t.Fatalf("jobs = %v", out.Jobs)
+	}
+	ran, idleJob := out.Jobs[0], out.Jobs[1]
+	if ran["name"] != "report" || ran["status"] != "ready" || ran["last_error"] != "boom" || ran["failures"] != 1.0 {
+		t.Errorf("job = %v", ran)
+	}
+	if d, ok := ran["last_duration"].(string); !ok || !strings.HasSuffix(d, "s") {
+		t.Errorf("last_duration = %v", ran["last_duration"])
+	}
+	// 从未执行的任务不输出耗时和错误
+	for _, key := range []string{"name", "last_duration", "last_error"} {
+		if _, ok := idleJob[key]; ok {
+			t.Errorf("idle job has %s: %v", key, idleJob)
+		}
+	}
+	if idleJob["status"] != "paused" {
+		t.Errorf("status = %v, want paused", idleJob["status"])
+	}
+
+	// 没有任务时输出空数组
+	if data, _ := json.Marshal(NewCron(WithLogger(DiscardLogger)).Snapshot()); !bytes.Contains(data, []byte(`"jobs":[]`)) {
+		t.Errorf("empty snapshot = %s", data)
+	}
+}
+
+// syncBuffer 并发安全的 bytes.Buffer
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) lines() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return strings.Split(strings.TrimSuffix(b.buf.String(), "\n"), "\n")
+}
+
+func TestSnapshotWriter(t *testing.T) {
+	var buf syncBuffer
+	c := NewCron(WithSnapshotWriter(&buf, 5*time.Millisecond), WithLogger(DiscardLogger))
+	c.AddJob("0 0 9 * * *", func() {}, WithName("report"))
+	c.Start()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(buf.lines()) < 2 {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for snapshots")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	<-c.Stop().Done()
+	time.Sleep(10 * time.Millisecond)
+
+	// Stop 之后不再写入
+	lines := buf.lines()
+	time.Sleep(20 * time.Millisecond)
+	if n := len(buf.lines()); n != len(lines) {
+		t.Errorf("%d snapshots after Stop, had %d", n, len(lines))
+	}
+	var snap struct {
+		Running bool `json:"running"`
+		Jobs    []struct {
+			Name string `json:"name"`
+		} `json:"jobs"`
+	}
+	if err := json.Unmarshal([]byte(lines[0]), &snap); err != nil || !snap.Running || len(snap.Jobs) != 1 || snap.Jobs[0].Name != "report" {
+		t.Errorf("first line %q: %+v, %v", lines[0], snap, err)
+	}
+}
@@ -0,0 +1,175 @@
+package cron
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// counters 任务的计数器，均为原子操作
+type counters struct {
+	abandoned uint64
+	// lastRun 最近一次开始执行的时间，UnixNano
+	lastRun int64
+	// rejected 因积压被拒绝的手动调用次数
+	rejected uint64
+	// manual 进行中的手动调用数量
+	manual int64
+	// inflight 正在执行的次数，不区分执行策略
+	inflight int64
+	// runs 开始执行的次数
+	runs uint64
+	// successes 正常结束的次数
+	successes uint64
+	// failures panic 或返回错误的次数
+	failures uint64
+	// skipped 被跳过的触发次数
+	skipped uint64
+	// lastDuration 最近一次执行的耗时
+	lastDuration int64
+	// timedOut 超过 WithTimeout 的执行次数
+	timedOut uint64
+	// force 为 1 时下一次定时触发跳过执行策略，见 ForceNext
+	// 32 位字段放在最后，保证前面的 64 位字段对齐
+	force int32
+}
+
+// result 最近一次执行的结果
+type result struct {
+	mu  sync.Mutex
+	err error
+}
+
+func (r *result) set(err error) {
+	r.mu.Lock()
+	r.err = err
+	r.mu.Unlock()
+}
+
+func (r *result) get() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.err
+}
+
+// JobStats 任务的统计信息快照
+type JobStats struct {
+	// ID 任务 ID
+	ID int
+	// Status 任务状态
+	Status uint
+	// Specs 任务的 spec，分组任务会有多个
+	Specs []string
+	// Group 任务所属的分组，见 WithGroup
+	Group string
+	// AddedAt 任务注册时间
+	AddedAt time.Time
+	// Abandoned 超过 HardTimeout 被放弃的执行次数
+	Abandoned uint64
+	// LastRun 最近一次开始执行的时间，从未执行为零值
+	LastRun time.Time
+	// Rejected 因积压被拒绝的手动调用次数，见 WithManualBacklog
+	Rejected uint64
+	// Runs 开始执行的次数，包括定时触发、立即执行和 Call
+	Runs uint64
+	// Successes 正常结束的次数
+	Successes uint64
+	// Failures panic 或返回错误的次数，重试只在最终失败时计一次
+	Failures uint64
+	// Skipped 被执行策略或幂等键跳过的触发次数
+	Skipped uint64
+	// LastDuration 最近一次执行的耗时
+	LastDuration time.Duration
+	// LastError 最近一次执行的错误，panic 也会转换为错误，成功时为 nil
+	LastError error
+	// TimedOut 超过 WithTimeout 的执行次数，在超时的时刻计入，不等执行结束
+	TimedOut uint64
+}
+
+// Stats 返回任务的统计信息，id 不存在返回 false
+func (s *Cron) Stats(id int) (JobStats, bool) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	entryI, ok := s.entry.Load(id)
+	if !ok {
+		return JobStats{}, false
+	}
+	return entryI.(*entry).stats(id), true
+}
+
+// stats 生成统计快照，调用方需持有读锁
+func (e *entry) stats(id int) JobStats {
+	return JobStats{
+		ID:           id,
+		Status:       e.getStatus(),
+		Specs:        append([]string(nil), e.specs...),
+		Group:        e.opt.Group,
+		AddedAt:      e.addedAt,
+		Abandoned:    atomic.LoadUint64(&e.counters.abandoned),
+		LastRun:      unixNano(atomic.LoadInt64(&e.counters.lastRun)),
+		Rejected:     atomic.LoadUint64(&e.counters.rejected),
+		Runs:         atomic.LoadUint64(&e.counters.runs),
+		Successes:    atomic.LoadUint64(&e.counters.successes),
+		Failures:     atomic.LoadUint64(&e.counters.failures),
+		Skipped:      atomic.LoadUint64(&e.counters.skipped),
+		LastDuration: time.Duration(atomic.LoadInt64(&e.counters.lastDuration)),
+		LastError:    e.result.get(),
+		TimedOut:     atomic.LoadUint64(&e.counters.timedOut),
+	}
+}
+
+// ResetStats 清零任务的计数和最近一次执行的结果，LastRun 和 AddedAt 保持不变
+// id 不存在时不做任何操作
+func (s *Cron) ResetStats(id int) {
+	e, ok := s.load(id)
+	if !ok {
+		return
+	}
+	c := &e.counters
+	for _, n := range []*uint64{&c.abandoned, &c.rejected, &c.runs, &c.successes, &c.failures, &c.skipped, &c.timedOut} {
+		atomic.StoreUint64(n, 0)
+	}
+	atomic.StoreInt64(&c.lastDuration, 0)
+	e.result.set(nil)
+}
+
+// unixNano 将 UnixNano 转换为时间，0 对应零值
+func unixNano(n int64) time.Time {
+	if n == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, n)
+}
+
+// AddedAt 返回任务的注册时间，id 不存在返回 false
+func (s *Cron) AddedAt(id int) (time.Time, bool) {
+	st, ok := s.Stats(id)
+	return st.AddedAt, ok
+}
+
+// snapshot 在同一把读锁下生成所有任务的统计快照，按 id 排序
+func (s *Cron) snapshot() []JobStats {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	var out []JobStats
+	s.entry.Range(func(key, value interface{}) bool {
+		out = append(out, value.(*entry).stats(key.(int)))
+		return true
+	})
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].ID < out[j].ID
+	})
+	return out
+}
+
+// JobsByStatus 返回当前处于 status 状态的任务 id，按 id 排序
+func (s *Cron) JobsByStatus(status uint) []int {
+	var ids []int
+	for _, st := range s.snapshot() {
+		if st.Status == status {
+			ids = append(ids, st.ID)
+		}
+	}
+	return ids
+}
@@ -0,0 +1,73 @@
+package cron
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// waitStats 等待任务的统计信息满足 cond，执行结束后统计信息才会更新
+func waitStats(t *testing.T, c *Cron, id int, cond func(JobStats) bool) JobStats {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		st, ok := c.Stats(id)
+		if !ok {
+			t.Fatalf("job %d not found", id)
+		}
+		if cond(st) {
+			return st
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for stats, last %+v", st)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestImmediateRunCountedInStats(t *testing.T) {
+	c := NewCron(WithLogger(DiscardLogger))
+	c.Start()
+	defer c.Stop()
+
+	ran := make(chan struct{}, 2)
+	id := c.AddJob("0 0 9 1 1 *", func() { ran <- struct{}{} }, WithImmediately(true), WithHistorySize(4))
+	receive(t, ran)
+	st := waitStats(t, c, id, func(st JobStats) bool { return st.Successes == 1 })
+	if st.Runs != 1 || st.Failures != 0 || st.Skipped != 0 {
+		t.Errorf("stats after the immediate run: %+v", st)
+	}
+	if st.LastRun.IsZero() {
+		t.Error("LastRun not set by the immediate run")
+	}
+	info, _ := c.jobInfo(id)
+	if info.Runs != 1 || !info.LastRun.Equal(st.LastRun) {
+		t.Errorf("JobInfo Runs %d, LastRun %v; want 1, %v", info.Runs, info.LastRun, st.LastRun)
+	}
+	if h := c.History(id, 0); len(h) != 1 || h[0].Outcome != OutcomeSuccess {
+		t.Errorf("history = %+v, want one success", h)
+	}
+
+	// 之后的 Call 在立即执行的基础上累加
+	c.Call(id)
+	receive(t, ran)
+	waitStats(t, c, id, func(st JobStats) bool { return st.Runs == 2 && st.Successes == 2 })
+}
+
+func TestImmediateFailureCountedInStats(t *testing.T) {
+	c := NewCron(WithLogger(DiscardLogger))
+	c.Start()
+	defer c.Stop()
+
+	id := c.AddJobE2("0 0 9 1 1 *", func() error { return errors.New("boom") }, WithImmediately(true))
+	st := waitStats(t, c, id, func(st JobStats) bool { return st.Failures == 1 })
+	if st.Runs != 1 || st.Successes != 0 || st.LastError == nil {
+		t.Errorf("stats after the failed immediate run: %+v", st)
+	}
+
+	panicked := c.AddJob("0 0 9 1 1 *", func() { panic("boom") }, WithImmediately(true))
+	st = waitStats(t, c, panicked, func(st JobStats) bool { return st.Failures == 1 })
+	if st.Runs != 1 || st.Successes != 0 {
+		t.Errorf("stats after the panicking immediate run: %+v", st)
+	}
+}
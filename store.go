@@ -0,0 +1,104 @@
+package cron
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// Store 持久化任务的运行状态，用于进程重启后判断错过的执行
+// key 为 "name:" 加任务名，未设置 WithName 时为 "id:" 加任务 id，多实例或重启后需要对应时请设置任务名
+type Store interface {
+	// SaveLastRun 保存任务最近一次开始执行的时间
+	SaveLastRun(key string, t time.Time) error
+	// LoadLastRun 读取任务最近一次开始执行的时间，没有记录返回 false
+	LoadLastRun(key string) (t time.Time, ok bool, err error)
+}
+
+type _Store struct {
+	Store
+}
+
+func (st _Store) applyCron(opts *cronOptions) {
+	opts.Store = st.Store
+}
+
+// WithStore 设置持久化存储，Stop 时会保存每个任务最近一次的执行时间，Start 时读回尚未执行过的任务的记录，
+// 之后 LastRun 为上一个进程最后一次执行的时间；错过的执行不会自动补上，可以用 DryRun(LastRun, now) 计算
+func WithStore(st Store) CronOption {
+	return _Store{st}
+}
+
+// persistOnStop 保存所有执行过的任务的最近执行时间，未设置 Store 时什么也不做
+// 设置了 JobStore 时同时更新 AddStoredJob 添加的任务的记录
+func (s *Cron) persistOnStop() {
+	if s.jobs != nil {
+		for _, st := range s.snapshot() {
+			if err := s.persistJob(st.ID); err != nil {
+				s.logger.Error(err, "save stored job failed", "id", st.ID)
+			}
+		}
+	}
+	if s.store == nil {
+		return
+	}
+	type lastRun struct {
+		id  int
+		key string
+		at  time.Time
+	}
+	var runs []lastRun
+	s.lock.RLock()
+	s.entry.Range(func(_, value interface{}) bool {
+		e := value.(*entry)
+		if at := unixNano(atomic.LoadInt64(&e.counters.lastRun)); !at.IsZero() {
+			runs = append(runs, lastRun{id: e.id, key: storeKey(e.id, e.opt.Name), at: at})
+		}
+		return true
+	})
+	s.lock.RUnlock()
+
+	for _, r := range runs {
+		if err := s.store.SaveLastRun(r.key, r.at); err != nil {
+			s.logger.Error(err, "save last run failed", "id", r.id)
+		}
+	}
+}
+
+// storeKey 任务在 Store 中的 key
+func storeKey(id int, name string) string {
+	if name != "" {
+		return "name:" + name
+	}
+	return fmt.Sprintf("id:%d", id)
+}
+
+// loadLastRuns 从 Store 读回尚未执行过的任务最近一次的执行时间，未设置 Store 时什么也不做
+func (s *Cron) loadLastRuns() {
+	if s.store == nil {
+		return
+	}
+	type job struct {
+		e   *entry
+		key string
+	}
+	var jobs []job
+	s.lock.RLock()
+	s.entry.Range(func(_, value interface{}) bool {
+		e := value.(*entry)
+		jobs = append(jobs, job{e: e, key: storeKey(e.id, e.opt.Name)})
+		return true
+	})
+	s.lock.RUnlock()
+
+	for _, j := range jobs {
+		t, ok, err := s.store.LoadLastRun(j.key)
+		if err != nil {
+			s.logger.Error(err, "load last run failed", "id", j.e.id)
+			continue
+		}
+		if ok && !t.IsZero() {
+			atomic.CompareAndSwapInt64(&j.e.counters.lastRun, 0, t.UnixNano())
+		}
+	}
+}
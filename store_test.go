@@ -0,0 +1,84 @@
+package cron
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// memStore 保存在内存中的 Store
+type memStore struct {
+	mu   sync.Mutex
+	runs map[string]time.Time
+}
+
+func newMemStore() *memStore {
+	return &memStore{runs: make(map[string]time.Time)}
+}
+
+func (m *memStore) SaveLastRun(key string, t time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.runs[key] = t
+	return nil
+}
+
+func (m *memStore) LoadLastRun(key string) (time.Time, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	t, ok := m.runs[key]
+	return t, ok, nil
+}
+
+func TestStoreKeysDoNotCollide(t *testing.T) {
+	store := newMemStore()
+	c := NewCron(WithStore(store), WithLogger(DiscardLogger))
+	a := c.AddJob("0 0 9 * * *", func() {}, WithName("a"))
+	b := c.AddJob("0 0 9 * * *", func() {}, WithName("b"))
+	unnamed := c.AddJob("0 0 9 * * *", func() {})
+	c.Start()
+	c.Call(a)
+	time.Sleep(10 * time.Millisecond)
+	c.Call(b)
+	c.Call(unnamed)
+	<-c.Stop().Done()
+
+	if len(store.runs) != 3 {
+		t.Fatalf("saved %v, want 3 keys", store.runs)
+	}
+	for _, key := range []string{"name:a", "name:b", storeKey(unnamed, "")} {
+		if _, ok := store.runs[key]; !ok {
+			t.Errorf("missing key %q in %v", key, store.runs)
+		}
+	}
+	if !store.runs["name:a"].Before(store.runs["name:b"]) {
+		t.Errorf("last runs overwrote each other: %v", store.runs)
+	}
+}
+
+func TestStoreLastRunRestoredOnStart(t *testing.T) {
+	store := newMemStore()
+	last := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	store.runs["name:report"] = last
+
+	c := NewCron(WithStore(store), WithLogger(DiscardLogger))
+	id := c.AddJob("0 0 9 * * *", func() {}, WithName("report"))
+	other := c.AddJob("0 0 9 * * *", func() {}, WithName("other"))
+	c.Start()
+	defer c.Stop()
+
+	if info, _ := c.jobInfo(id); !info.LastRun.Equal(last) {
+		t.Errorf("LastRun = %v, want %v", info.LastRun, last)
+	}
+	if info, _ := c.jobInfo(other); !info.LastRun.IsZero() {
+		t.Errorf("job without record has LastRun %v", info.LastRun)
+	}
+}
+
+func TestStoreNotConfigured(t *testing.T) {
+	c := NewCron(WithLogger(DiscardLogger))
+	id := c.AddJob("0 0 9 * * *", func() {})
+	c.Start()
+	c.Call(id)
+	<-c.Stop().Done()
+}
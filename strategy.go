@@ -0,0 +1,212 @@
+package cron
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// RunStrategy 执行策略，决定任务每次触发时如何执行
+// 内置策略见 WithRunMode，也可以通过 WithRunStrategy 自定义
+type RunStrategy interface {
+	// Execute 任务触发时调用，id 为任务 ID，run 为实际要执行的函数
+	Execute(id int, run func())
+}
+
+type _Strategy struct {
+	RunStrategy
+}
+
+func (st _Strategy) apply(opts *options) {
+	opts.Strategy = st.RunStrategy
+}
+
+// WithRunStrategy 使用自定义执行策略，设置后 WithRunMode 不再生效
+func WithRunStrategy(st RunStrategy) Option {
+	return _Strategy{st}
+}
+
+type _MaxConcurrency int
+
+func (n _MaxConcurrency) apply(opts *options) {
+	opts.MaxConcurrency = int(n)
+}
+
+func (n _MaxConcurrency) applyCron(opts *cronOptions) {
+	opts.MaxConcurrency = int(n)
+}
+
+// WithMaxConcurrency 限制同时执行的次数
+// 添加任务时传入设置 ModeJobParallel 下该任务同时执行的上限，超出的触发会被跳过，reason 为 SkipReasonConcurrency，
+// n 小于 1 时按 1 处理，即与 ModeJobSerial 相同；其他 RunMode 下不生效
+// 传给 NewCron 时限制所有任务加起来同时执行的次数，n 小于 1 表示不限制；
+// 超出的执行会等待空出的名额而不是被跳过，等待期间任务已处于运行状态，调度器 Stop 时放弃等待
+func WithMaxConcurrency(n int) CommonOption {
+	return _MaxConcurrency(n)
+}
+
+// limit 包装任务函数，执行前等待全局的并发名额
+func (s *Cron) limit(f execFunc) execFunc {
+	return func(t trigger) {
+		s.lock.RLock()
+		done := s.root.Done()
+		s.lock.RUnlock()
+
+		select {
+		case s.slots <- struct{}{}:
+		case <-done:
+			return
+		}
+		defer func() { <-s.slots }()
+		f(t)
+	}
+}
+
+type _QueueDepth int
+
+func (n _QueueDepth) apply(opts *options) {
+	opts.QueueDepth = int(n)
+}
+
+// WithQueueDepth 设置 ModeJobQueue 下最多排队的执行数，超出的触发会被跳过，reason 为 SkipReasonQueueFull
+// n 小于 0 时按 0 处理，即不排队，与 ModeJobSerial 相同；其他 RunMode 下不生效
+func WithQueueDepth(n int) Option {
+	return _QueueDepth(n)
+}
+
+// builtinStrategy 返回 RunMode 对应的内置策略
+func (s *Cron) builtinStrategy(opt options) RunStrategy {
+	switch opt.RunMode {
+	case ModeJobSerial:
+		return serialStrategy{s}
+	case ModeJobParallel:
+		n := opt.MaxConcurrency
+		if n < 1 {
+			n = 1
+		}
+		return parallelStrategy{c: s, limit: n}
+	case ModeJobQueue:
+		n := opt.QueueDepth
+		if n < 0 {
+			n = 0
+		}
+		return queueStrategy{c: s, depth: n}
+	default:
+		return timeFirstStrategy{}
+	}
+}
+
+// scheduledStrategy 内置的执行策略，跳过时需要知道本次的触发时间
+type scheduledStrategy interface {
+	executeAt(id int, at time.Time, run func())
+}
+
+// serialStrategy 对应 ModeJobSerial，上一次执行未结束时跳过本次
+type serialStrategy struct {
+	c *Cron
+}
+
+func (st serialStrategy) Execute(id int, run func()) {
+	st.executeAt(id, time.Now(), run)
+}
+
+func (st serialStrategy) executeAt(id int, at time.Time, run func()) {
+	if !st.c.acquire(id) {
+		st.c.skip(id, SkipReasonSerial, at)
+		return
+	}
+	defer st.c.release(id)
+	run()
+}
+
+// timeFirstStrategy 对应 ModeTimeFirst，每次触发都执行
+type timeFirstStrategy struct{}
+
+func (timeFirstStrategy) Execute(_ int, run func()) {
+	run()
+}
+
+// parallelStrategy 对应 ModeJobParallel，同时执行的次数达到上限时跳过本次
+type parallelStrategy struct {
+	c     *Cron
+	limit int
+}
+
+func (st parallelStrategy) Execute(id int, run func()) {
+	st.executeAt(id, time.Now(), run)
+}
+
+func (st parallelStrategy) executeAt(id int, at time.Time, run func()) {
+	if !st.c.acquireN(id, st.limit) {
+		st.c.skip(id, SkipReasonConcurrency, at)
+		return
+	}
+	defer st.c.release(id)
+	run()
+}
+
+// queueStrategy 对应 ModeJobQueue，上一次执行未结束时排队，排队数达到上限时跳过本次
+type queueStrategy struct {
+	c     *Cron
+	depth int
+}
+
+func (st queueStrategy) Execute(id int, run func()) {
+	st.executeAt(id, time.Now(), run)
+}
+
+func (st queueStrategy) executeAt(id int, at time.Time, run func()) {
+	if !st.c.acquireN(id, st.depth+1) {
+		st.c.skip(id, SkipReasonQueueFull, at)
+		return
+	}
+	defer st.c.release(id)
+
+	st.c.lock.RLock()
+	e, ok := st.c.load(id)
+	done := st.c.root.Done()
+	st.c.lock.RUnlock()
+	if !ok {
+		return
+	}
+	// 排队期间调度器 Stop 时放弃本次执行
+	select {
+	case e.turn <- struct{}{}:
+	case <-done:
+		return
+	}
+	defer func() { <-e.turn }()
+	run()
+}
+
+// skip 记录任务本次触发被跳过，并触发 OnSkip 回调，at 为本次的触发时间
+func (s *Cron) skip(id int, reason string, at time.Time) {
+	if e, opt, ok := s.loadOptions(id); ok {
+		atomic.AddUint64(&e.counters.skipped, 1)
+		e.history.append(RunRecord{Start: at, Outcome: OutcomeSkipped, SkipReason: reason})
+		l, kv := s.jobLogger(id)
+		l.Info("job skipped", append(kv, "reason", reason)...)
+		s.eachHooks(opt, func(h Hooks) {
+			if h.OnSkip != nil {
+				h.OnSkip(id, reason)
+			}
+		})
+		opt.skip(id, reason, at)
+	}
+}
+
+// ForceNext 让任务的下一次定时触发绕过执行策略执行一次，之后恢复正常
+// 适合需要强制刷新、但又不想把任务永久切换为 ModeTimeFirst 的场景
+// ModeJobSerial 下如果上一次执行还没结束，两次执行会并发进行，
+// 强制执行不会修改任务状态，任务需要自行保证并发安全
+// 只影响定时触发，Call 和立即执行不会消耗该标记
+func (s *Cron) ForceNext(id int) {
+	if e, ok := s.load(id); ok {
+		atomic.StoreInt32(&e.counters.force, 1)
+	}
+}
+
+// takeForce 取出并清除 ForceNext 标记
+func (s *Cron) takeForce(id int) bool {
+	e, ok := s.load(id)
+	return ok && atomic.CompareAndSwapInt32(&e.counters.force, 1, 0)
+}
@@ -0,0 +1,111 @@
+package cron
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+type _HardTimeout time.Duration
+
+func (d _HardTimeout) apply(opts *options) {
+	opts.HardTimeout = time.Duration(d)
+}
+
+// WithHardTimeout 单次执行超过 d 时将其标记为被放弃，计入 JobStats.Abandoned
+// Go 无法强制结束 goroutine，被放弃的执行仍会在后台继续运行直到返回
+func WithHardTimeout(d time.Duration) Option {
+	return _HardTimeout(d)
+}
+
+type _ReleaseOnAbandon bool
+
+func (r _ReleaseOnAbandon) apply(opts *options) {
+	opts.ReleaseOnAbandon = bool(r)
+}
+
+// WithReleaseOnAbandon 执行被放弃时立即释放运行状态
+// ModeJobSerial 下这会让下一次触发正常执行，避免一次卡死的执行阻塞后续所有执行，
+// 但被放弃的执行仍在后台运行，两者可能并发执行，任务需要自行保证并发安全
+func WithReleaseOnAbandon(r bool) Option {
+	return _ReleaseOnAbandon(r)
+}
+
+// hardTimeout 包装任务函数，超时后标记为被放弃
+// 开启 ReleaseOnAbandon 时超时即返回，由执行策略释放运行状态；
+// 被放弃的执行结束时不会再修改状态，不会影响之后新开始的执行
+func (s *Cron) hardTimeout(id int, f execFunc, opt options) execFunc {
+	return func(t trigger) {
+		runWithin(func() { f(t) }, opt.HardTimeout, opt.ReleaseOnAbandon, func() {
+			if e, ok := s.load(id); ok {
+				atomic.AddUint64(&e.counters.abandoned, 1)
+			}
+		})
+	}
+}
+
+type _Timeout time.Duration
+
+func (d _Timeout) apply(opts *options) {
+	opts.Timeout = time.Duration(d)
+}
+
+// WithTimeout 单次执行超过 d 时不再视为正在运行：运行状态被释放，下一次触发可以正常执行，
+// AddJobContext 任务的 ctx 会被取消，并通过 TimeoutHandler 报告，0 表示不限制
+// 超时计入 JobStats.TimedOut，执行记录的 TimedOut 为 true
+// 超时的执行仍会在后台继续运行，它结束时不会再修改运行状态，
+// 不会把之后新开始的执行错误地标记为 StatusReady
+func WithTimeout(d time.Duration) Option {
+	return _Timeout(d)
+}
+
+type _TimeoutHandler func(id int, timeout time.Duration)
+
+func (f _TimeoutHandler) apply(opts *options) {
+	opts.TimeoutHandler = f
+}
+
+// WithTimeoutHandler 设置执行超过 WithTimeout 时的回调，未设置时交给 Logger
+func WithTimeoutHandler(f func(id int, timeout time.Duration)) Option {
+	return _TimeoutHandler(f)
+}
+
+// timeout 包装任务函数，超时即返回，由执行策略释放运行状态
+func (s *Cron) timeout(id int, f execFunc, opt options) execFunc {
+	return func(t trigger) {
+		runWithin(func() { f(t) }, opt.Timeout, true, func() {
+			if e, ok := s.load(id); ok {
+				atomic.AddUint64(&e.counters.timedOut, 1)
+			}
+			if opt.TimeoutHandler != nil {
+				opt.TimeoutHandler(id, opt.Timeout)
+				return
+			}
+			l, kv := s.jobLogger(id)
+			l.Error(ErrTimeout, "job timed out", append(kv, "timeout", opt.Timeout)...)
+		})
+	}
+}
+
+// runWithin 执行 f，超过 d 仍未结束时调用 expired，
+// release 为 true 时超时后立即返回，否则继续等待 f 结束
+func runWithin(f func(), d time.Duration, release bool, expired func()) {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		f()
+	}()
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-done:
+		return
+	case <-timer.C:
+	}
+
+	expired()
+	if release {
+		return
+	}
+	<-done
+}
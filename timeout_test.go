@@ -0,0 +1,170 @@
+package cron
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// waitAbandoned 等待任务的 Abandoned 计数达到 n
+func waitAbandoned(t *testing.T, c *Cron, id int, n uint64) {
+	t.Helper()
+	waitStats(t, c, id, func(st JobStats) bool { return st.Abandoned == n })
+}
+
+func TestHardTimeoutKeepsSerialGate(t *testing.T) {
+	c := NewCron(WithLogger(DiscardLogger))
+	c.Start()
+	defer c.Stop()
+	block := make(chan struct{})
+	t.Cleanup(func() { close(block) })
+	started := make(chan struct{}, 2)
+	id := c.AddJob("0 0 9 * * *", func() {
+		started <- struct{}{}
+		<-block
+	}, WithHardTimeout(20*time.Millisecond))
+
+	c.CallAsync(id)
+	receive(t, started)
+	waitAbandoned(t, c, id, 1)
+
+	// 未开启 WithReleaseOnAbandon 时被放弃的执行仍然占用运行状态
+	if got := c.GetStatus(id); got != StatusRunning {
+		t.Errorf("status = %d, want StatusRunning", got)
+	}
+	if c.CallAsync(id) {
+		t.Error("second run started while the abandoned one holds the gate")
+	}
+}
+
+func TestHardTimeoutReleaseOnAbandon(t *testing.T) {
+	c := NewCron(WithLogger(DiscardLogger))
+	c.Start()
+	defer c.Stop()
+	first, second := make(chan struct{}), make(chan struct{})
+	t.Cleanup(func() {
+		close(first)
+		close(second)
+	})
+	blocks := make(chan chan struct{}, 2)
+	blocks <- first
+	blocks <- second
+	started := make(chan struct{}, 2)
+	id := c.AddJob("0 0 9 * * *", func() {
+		block := <-blocks
+		started <- struct{}{}
+		<-block
+	}, WithHardTimeout(20*time.Millisecond), WithReleaseOnAbandon(true))
+
+	c.CallAsync(id)
+	receive(t, started)
+	waitAbandoned(t, c, id, 1)
+	waitIdle(t, c, id)
+
+	// 运行状态已释放，下一次执行可以开始，与被放弃的执行并发
+	if !c.CallAsync(id) {
+		t.Fatal("run after the abandon did not start")
+	}
+	receive(t, started)
+
+	// 被放弃的执行结束时不影响正在进行的执行
+	first <- struct{}{}
+	time.Sleep(5 * time.Millisecond)
+	if got := c.GetStatus(id); got != StatusRunning {
+		t.Errorf("status = %d after the abandoned run finished, want StatusRunning", got)
+	}
+	if st, _ := c.Stats(id); st.Runs != 2 {
+		t.Errorf("runs = %d, want 2", st.Runs)
+	}
+}
+
+func TestHardTimeoutFastRun(t *testing.T) {
+	c := NewCron(WithLogger(DiscardLogger))
+	id := c.AddJob("0 0 9 * * *", func() {}, WithHardTimeout(time.Second))
+	c.Call(id)
+	if st, _ := c.Stats(id); st.Abandoned != 0 || st.Successes != 1 {
+		t.Errorf("stats = %+v", st)
+	}
+}
+
+func TestTimeoutReleasesSerialGate(t *testing.T) {
+	c := NewCron(WithLogger(DiscardLogger))
+	c.Start()
+	defer c.Stop()
+	timeouts := make(chan time.Duration, 2)
+	first, second := make(chan struct{}), make(chan struct{})
+	t.Cleanup(func() { close(second) })
+	blocks := make(chan chan struct{}, 2)
+	blocks <- first
+	blocks <- second
+	started := make(chan struct{}, 2)
+	id := c.AddJob("0 0 9 * * *", func() {
+		block := <-blocks
+		started <- struct{}{}
+		<-block
+	}, WithTimeout(20*time.Millisecond), WithTimeoutHandler(func(_ int, d time.Duration) { timeouts <- d }))
+
+	c.CallAsync(id)
+	receive(t, started)
+	if d := receive(t, timeouts); d != 20*time.Millisecond {
+		t.Errorf("handler got %v", d)
+	}
+	waitIdle(t, c, id)
+	if st, _ := c.Stats(id); st.TimedOut != 1 {
+		t.Errorf("timed out = %d, want 1", st.TimedOut)
+	}
+
+	// 超时之后下一次执行正常开始，超时的执行结束时不会把它标记为 StatusReady
+	if !c.CallAsync(id) {
+		t.Fatal("run after the timeout did not start")
+	}
+	receive(t, started)
+	close(first)
+	time.Sleep(5 * time.Millisecond)
+	if got := c.GetStatus(id); got != StatusRunning {
+		t.Errorf("status = %d after the timed out run finished, want StatusRunning", got)
+	}
+}
+
+func TestTimeoutLogsWithoutHandler(t *testing.T) {
+	logger := &errorLogger{}
+	c := NewCron(WithLogger(logger))
+	id := c.AddJob("0 0 9 * * *", func() { time.Sleep(20 * time.Millisecond) }, WithTimeout(5*time.Millisecond))
+	fast := c.AddJob("0 0 9 * * *", func() {}, WithTimeout(time.Second))
+	c.Call(id)
+	c.Call(fast)
+	if n := atomic.LoadInt32(&logger.errors); n != 1 {
+		t.Errorf("%d errors logged, want 1", n)
+	}
+	if st, _ := c.Stats(fast); st.TimedOut != 0 {
+		t.Errorf("fast run timed out: %+v", st)
+	}
+}
+
+func TestTimeoutCancelsContext(t *testing.T) {
+	c := NewCron(WithLogger(DiscardLogger))
+	c.Start()
+	defer c.Stop()
+	cancelled := make(chan error, 1)
+	id := c.AddJobContext("0 0 9 * * *", func(ctx context.Context) {
+		<-ctx.Done()
+		cancelled <- ctx.Err()
+	}, WithTimeout(20*time.Millisecond), WithTimeoutHandler(func(int, time.Duration) {}), WithHistorySize(2))
+
+	start := time.Now()
+	c.CallAsync(id)
+	if err := receive(t, cancelled); err == nil {
+		t.Error("ctx ended without an error")
+	}
+	if d := time.Since(start); d < 20*time.Millisecond {
+		t.Errorf("ctx cancelled after %v, before the timeout", d)
+	}
+
+	// 执行记录标记为超时
+	waitStats(t, c, id, func(st JobStats) bool { return st.Successes+st.Failures == 1 })
+	runs := c.History(id, 1)
+	if len(runs) != 1 || !runs[0].TimedOut || runs[0].Outcome != OutcomeTimeout {
+		t.Errorf("history = %+v", runs)
+	}
+}
@@ -0,0 +1,116 @@
+package cron
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// tracerName 向 TracerProvider 获取 Tracer 时使用的名字
+const tracerName = "github.com/kainhuck/cron"
+
+// TracerProvider 创建 Tracer，本包不依赖 OpenTelemetry，以 go.opentelemetry.io/otel 为例，适配方式如下：
+//
+//	type provider struct{ trace.TracerProvider }
+//
+//	func (p provider) Tracer(name string) cron.Tracer { return tracer{p.TracerProvider.Tracer(name)} }
+//
+//	type tracer struct{ trace.Tracer }
+//
+//	func (t tracer) Start(ctx context.Context, name string) (context.Context, cron.Span) {
+//		ctx, span := t.Tracer.Start(ctx, name)
+//		return ctx, otelSpan{span}
+//	}
+//
+//	type otelSpan struct{ trace.Span }
+//
+//	func (s otelSpan) SetAttribute(key string, value interface{}) {
+//		s.Span.SetAttributes(attribute.String(key, fmt.Sprint(value)))
+//	}
+//
+//	func (s otelSpan) RecordError(err error) {
+//		s.Span.RecordError(err)
+//		s.Span.SetStatus(codes.Error, err.Error())
+//	}
+//
+//	func (s otelSpan) End() { s.Span.End() }
+type TracerProvider interface {
+	Tracer(name string) Tracer
+}
+
+// Tracer 为每次执行创建 span
+type Tracer interface {
+	// Start 创建 span，返回的 ctx 携带该 span
+	Start(ctx context.Context, spanName string) (context.Context, Span)
+}
+
+// Span 一次执行对应的 span
+type Span interface {
+	// SetAttribute 设置属性
+	SetAttribute(key string, value interface{})
+	// RecordError 记录执行失败，err 为任务返回的错误，panic 时为 *PanicError，超时时为 ErrTimeout
+	RecordError(err error)
+	// End 结束 span
+	End()
+}
+
+type _TracerProvider struct {
+	TracerProvider
+}
+
+func (tp _TracerProvider) applyCron(opts *cronOptions) {
+	opts.TracerProvider = tp.TracerProvider
+}
+
+// WithTracerProvider 为每次执行创建 span，span 名为任务名，没有任务名时为 spec
+// span 包含整次执行，包括重试，带有 cron.job.id、cron.job.name、cron.job.spec 和 cron.outcome 属性；
+// AddJobContext 等任务收到的 ctx 携带该 span，任务内创建的 span 会成为它的子 span
+// 被跳过的触发不会创建 span
+func WithTracerProvider(tp TracerProvider) CronOption {
+	return _TracerProvider{tp}
+}
+
+// trace 包装任务函数，每次执行创建一个 span
+func (s *Cron) trace(id int, f jobFunc, opt options) jobFunc {
+	return func(ctx context.Context) (err error) {
+		s.lock.RLock()
+		var spec string
+		if e, ok := s.load(id); ok {
+			spec = strings.Join(e.specs, ";")
+		}
+		s.lock.RUnlock()
+
+		name := opt.Name
+		if name == "" {
+			name = spec
+		}
+		ctx, span := s.tracer.Start(ctx, name)
+		span.SetAttribute("cron.job.id", id)
+		if opt.Name != "" {
+			span.SetAttribute("cron.job.name", opt.Name)
+		}
+		span.SetAttribute("cron.job.spec", spec)
+
+		start := time.Now()
+		defer func() {
+			r := recover()
+			failure := err
+			if r != nil {
+				failure = &PanicError{Value: r}
+			}
+			timedOut := opt.Timeout > 0 && time.Since(start) > opt.Timeout
+			if timedOut && failure == nil {
+				failure = ErrTimeout
+			}
+			span.SetAttribute("cron.outcome", string(outcome(failure, r != nil, timedOut)))
+			if failure != nil {
+				span.RecordError(failure)
+			}
+			span.End()
+			if r != nil {
+				panic(r)
+			}
+		}()
+		return f(ctx)
+	}
+}
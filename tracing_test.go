@@ -0,0 +1,149 @@
+package cron
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// fakeSpan 记录 span 上的操作，done 在 End 时关闭
+type fakeSpan struct {
+	name  string
+	attrs map[string]interface{}
+	errs  []error
+	ended bool
+	done  chan struct{}
+}
+
+func (s *fakeSpan) SetAttribute(key string, value interface{}) { s.attrs[key] = value }
+func (s *fakeSpan) RecordError(err error)                      { s.errs = append(s.errs, err) }
+
+func (s *fakeSpan) End() {
+	s.ended = true
+	close(s.done)
+}
+
+type spanKey struct{}
+
+// fakeTracer 同时作为 TracerProvider，记录创建的 span 和请求的 tracer 名
+type fakeTracer struct {
+	mu    sync.Mutex
+	names []string
+	spans []*fakeSpan
+}
+
+func (tr *fakeTracer) Tracer(name string) Tracer {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	tr.names = append(tr.names, name)
+	return tr
+}
+
+func (tr *fakeTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	span := &fakeSpan{name: name, attrs: map[string]interface{}{}, done: make(chan struct{})}
+	tr.spans = append(tr.spans, span)
+	return context.WithValue(ctx, spanKey{}, span), span
+}
+
+func (tr *fakeTracer) get() []*fakeSpan {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	return append([]*fakeSpan(nil), tr.spans...)
+}
+
+func TestTracingSpanPerRun(t *testing.T) {
+	tr := &fakeTracer{}
+	c := NewCron(WithTracerProvider(tr), WithLogger(DiscardLogger))
+	var inJob *fakeSpan
+	id := c.AddJobContext("0 0 9 * * *", func(ctx context.Context) {
+		inJob, _ = ctx.Value(spanKey{}).(*fakeSpan)
+	}, WithName("report"))
+	c.Call(id)
+
+	if len(tr.names) == 0 || tr.names[0] != tracerName {
+		t.Errorf("tracer names = %v", tr.names)
+	}
+	spans := tr.get()
+	if len(spans) != 1 {
+		t.Fatalf("%d spans, want 1", len(spans))
+	}
+	span := spans[0]
+	if span.name != "report" || !span.ended || len(span.errs) != 0 {
+		t.Errorf("span = %+v", span)
+	}
+	want := map[string]interface{}{
+		"cron.job.id":   id,
+		"cron.job.name": "report",
+		"cron.job.spec": "0 0 9 * * *",
+		"cron.outcome":  string(OutcomeSuccess),
+	}
+	for k, v := range want {
+		if span.attrs[k] != v {
+			t.Errorf("%s = %v, want %v", k, span.attrs[k], v)
+		}
+	}
+	// 任务收到的 ctx 携带该 span
+	if inJob != span {
+		t.Error("job ctx does not carry the span")
+	}
+}
+
+func TestTracingUnnamedJob(t *testing.T) {
+	tr := &fakeTracer{}
+	c := NewCron(WithTracerProvider(tr), WithLogger(DiscardLogger))
+	c.Call(c.AddJob("0 30 10 * * *", func() {}))
+	spans := tr.get()
+	if len(spans) != 1 || spans[0].name != "0 30 10 * * *" {
+		t.Fatalf("spans = %+v", spans)
+	}
+	if _, ok := spans[0].attrs["cron.job.name"]; ok {
+		t.Error("unnamed job has cron.job.name")
+	}
+}
+
+func TestTracingFailures(t *testing.T) {
+	tr := &fakeTracer{}
+	c := NewCron(WithTracerProvider(tr), WithLogger(DiscardLogger))
+	boom := errors.New("boom")
+	failing := c.AddJobE2("0 0 9 * * *", func() error { return boom }, WithRetry(2, 0))
+	panicking := c.AddJob("0 0 9 * * *", func() { panic("boom") })
+	slow := c.AddJob("0 0 9 * * *", func() { time.Sleep(20 * time.Millisecond) }, WithTimeout(5*time.Millisecond))
+	c.Call(failing)
+	c.Call(panicking)
+	c.Call(slow)
+
+	spans := tr.get()
+	// 重试包含在同一个 span 中
+	if len(spans) != 3 {
+		t.Fatalf("%d spans, want one per execution", len(spans))
+	}
+	// 超时后 Call 先返回，span 在任务结束时才结束
+	receive(t, spans[2].done)
+	if s := spans[0]; s.attrs["cron.outcome"] != string(OutcomeError) || len(s.errs) != 1 || s.errs[0] != boom || !s.ended {
+		t.Errorf("error span = %+v", s)
+	}
+	var pe *PanicError
+	if s := spans[1]; s.attrs["cron.outcome"] != string(OutcomePanic) || len(s.errs) != 1 || !errors.As(s.errs[0], &pe) || !s.ended {
+		t.Errorf("panic span = %+v", s)
+	}
+	if s := spans[2]; s.attrs["cron.outcome"] != string(OutcomeTimeout) || len(s.errs) != 1 || !errors.Is(s.errs[0], ErrTimeout) {
+		t.Errorf("timeout span = %+v", s)
+	}
+}
+
+func TestTracingSkipsHaveNoSpan(t *testing.T) {
+	tr := &fakeTracer{}
+	c := NewCron(WithTracerProvider(tr), WithLogger(DiscardLogger))
+	id := c.AddJob("0 0 9 * * *", func() {}, WithRateLimit(rate.Every(time.Hour), 1))
+	c.Call(id)
+	c.Call(id)
+	if n := len(tr.get()); n != 1 {
+		t.Errorf("%d spans, want only the run that was not skipped", n)
+	}
+}
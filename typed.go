@@ -0,0 +1,103 @@
+package cron
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// typedFunc RegisterTypedFunc 注册的函数，根据保存的 payload 构造任务函数
+type typedFunc func(raw json.RawMessage) (payload interface{}, job jobFunc, err error)
+
+type _Payload struct {
+	value interface{}
+	raw   json.RawMessage
+}
+
+func (p _Payload) apply(opts *options) {
+	opts.Payload = p.value
+	opts.PayloadJSON = p.raw
+}
+
+// AddTypedJob 添加携带参数的任务，每次执行时将 payload 交给 f，f 返回的错误会计入失败次数和执行记录
+// payload 可以通过 ListJobs 的 JobInfo.Payload 查看，需要持久化请使用 AddStoredTypedJob
+// Go 的方法不支持类型参数，因此以函数形式提供；返回的 id 与 AddJob 相同，失败返回 -1
+func AddTypedJob[T any](s *Cron, spec string, payload T, f func(ctx context.Context, payload T) error, options ...Option) (id int) {
+	id, _ = AddTypedJobE(s, spec, payload, f, options...)
+	return id
+}
+
+// AddTypedJobE 同 AddTypedJob，但会返回失败原因
+func AddTypedJobE[T any](s *Cron, spec string, payload T, f func(ctx context.Context, payload T) error, options ...Option) (id int, err error) {
+	s.warnStopped(spec)
+
+	opt := applyOptions(append(options, _Payload{value: payload})...)
+	return s.addSpec(spec, func(int) jobFunc { return typed(payload, f) }, opt)
+}
+
+// typed 将携带参数的任务函数转换为内部使用的形式
+func typed[T any](payload T, f func(context.Context, T) error) jobFunc {
+	return func(ctx context.Context) error {
+		return f(ctx, payload)
+	}
+}
+
+// RegisterTypedFunc 注册携带参数的任务函数，AddStoredTypedJob 和 Restore 按 name 查找，
+// 恢复时将保存的 JSON 解码为 T 后交给 f；与 RegisterFunc 的名字互不影响，需要在 Start 之前注册
+func RegisterTypedFunc[T any](s *Cron, name string, f func(ctx context.Context, payload T) error) {
+	s.lock.Lock()
+	s.typedFuncs[name] = func(raw json.RawMessage) (interface{}, jobFunc, error) {
+		var payload T
+		if err := json.Unmarshal(raw, &payload); err != nil {
+			return nil, nil, fmt.Errorf("cron: decode payload of %s: %w", name, err)
+		}
+		return payload, typed(payload, f), nil
+	}
+	s.lock.Unlock()
+}
+
+// lookupTypedFunc 返回注册的携带参数的任务函数
+func (s *Cron) lookupTypedFunc(name string) (typedFunc, bool) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	f, ok := s.typedFuncs[name]
+	return f, ok
+}
+
+// AddStoredTypedJob 同 AddStoredJob，fn 为 RegisterTypedFunc 注册的函数名，payload 以 JSON 一起保存
+// 添加时会按注册的类型解码一次 payload，确保重启后能够恢复，payload 无法编码或与注册的类型不符时返回错误
+// name 为空返回 ErrNoName
+func AddStoredTypedJob[T any](s *Cron, name, spec, fn string, payload T, options ...Option) (id int, err error) {
+	if name == "" {
+		return -1, ErrNoName
+	}
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return -1, fmt.Errorf("cron: encode payload of %s: %w", fn, err)
+	}
+	id, err = s.addTyped(spec, fn, raw, append(options, WithName(name)))
+	if err != nil {
+		return -1, err
+	}
+	if err = s.persistJob(id); err != nil {
+		s.RemoveJob(id)
+		return -1, err
+	}
+	return id, nil
+}
+
+// addTyped 按函数名和 JSON 形式的 payload 添加任务
+func (s *Cron) addTyped(spec, fn string, raw json.RawMessage, options []Option) (id int, err error) {
+	build, ok := s.lookupTypedFunc(fn)
+	if !ok {
+		return -1, fmt.Errorf("%w: %s", ErrUnknownFunc, fn)
+	}
+	payload, job, err := build(raw)
+	if err != nil {
+		return -1, err
+	}
+	s.warnStopped(spec)
+
+	opt := applyOptions(append(options, _Func(fn), _Payload{value: payload, raw: raw})...)
+	return s.addSpec(spec, func(int) jobFunc { return job }, opt)
+}
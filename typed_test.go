@@ -0,0 +1,86 @@
+package cron
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+type reportPayload struct {
+	Region string
+	Limit  int
+}
+
+func TestTypedJobReceivesPayload(t *testing.T) {
+	c := NewCron(WithLogger(DiscardLogger))
+	got := make(chan reportPayload, 1)
+	payload := reportPayload{Region: "eu", Limit: 10}
+	id := AddTypedJob(c, "0 0 9 * * *", payload, func(ctx context.Context, p reportPayload) error {
+		if ctx == nil {
+			t.Error("nil ctx")
+		}
+		got <- p
+		return nil
+	}, WithName("report"))
+	if id < 0 {
+		t.Fatal("AddTypedJob failed")
+	}
+	c.Call(id)
+	if p := receive(t, got); p != payload {
+		t.Errorf("payload = %+v", p)
+	}
+	if info, _ := c.GetJobByName("report"); info.Payload != payload {
+		t.Errorf("JobInfo.Payload = %#v", info.Payload)
+	}
+}
+
+func TestTypedJobErrorCountsAsFailure(t *testing.T) {
+	c := NewCron(WithLogger(DiscardLogger))
+	boom := errors.New("boom")
+	id := AddTypedJob(c, "0 0 9 * * *", 1, func(context.Context, int) error { return boom })
+	c.Call(id)
+	if st, _ := c.Stats(id); st.Failures != 1 || st.Successes != 0 || !errors.Is(st.LastError, boom) {
+		t.Errorf("stats = %+v", st)
+	}
+}
+
+func TestTypedJobInvalidSpec(t *testing.T) {
+	c := NewCron(WithLogger(DiscardLogger))
+	id, err := AddTypedJobE(c, "bogus", 1, func(context.Context, int) error { return nil })
+	if id != -1 || err == nil {
+		t.Errorf("id %d, err %v", id, err)
+	}
+	if c.Count() != 0 {
+		t.Error("invalid job registered")
+	}
+}
+
+func TestStoredTypedJobErrors(t *testing.T) {
+	c := NewCron(WithJobStore(NewFileStore(filepath.Join(t.TempDir(), "jobs.json"))), WithLogger(DiscardLogger))
+	RegisterTypedFunc(c, "report", func(context.Context, reportPayload) error { return nil })
+
+	if id, err := AddStoredTypedJob(c, "a", "0 0 9 * * *", "missing", reportPayload{}); id != -1 || !errors.Is(err, ErrUnknownFunc) {
+		t.Errorf("unknown func: id %d, err %v", id, err)
+	}
+	// payload 与注册的类型不符
+	if id, err := AddStoredTypedJob(c, "b", "0 0 9 * * *", "report", "eu"); id != -1 || err == nil {
+		t.Errorf("mismatched payload: id %d, err %v", id, err)
+	}
+	if id, err := AddStoredTypedJob(c, "c", "0 0 9 * * *", "report", make(chan int)); id != -1 || err == nil {
+		t.Errorf("unencodable payload: id %d, err %v", id, err)
+	}
+	if c.Count() != 0 {
+		t.Errorf("jobs registered: %+v", c.ListJobs())
+	}
+
+	// 与 RegisterFunc 的名字互不影响
+	c.RegisterFunc("report", func() {})
+	id, err := AddStoredTypedJob(c, "d", "0 0 9 * * *", "report", reportPayload{Region: "us"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info, _ := c.jobInfo(id); info.Payload != (reportPayload{Region: "us"}) {
+		t.Errorf("payload = %#v", info.Payload)
+	}
+}
@@ -0,0 +1,151 @@
+package cron
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// JobError 某个任务的错误
+type JobError struct {
+	ID  int
+	Err error
+}
+
+func (e *JobError) Error() string {
+	return fmt.Sprintf("cron: job %d: %v", e.ID, e.Err)
+}
+
+func (e *JobError) Unwrap() error {
+	return e.Err
+}
+
+// PanicError 执行中发生了 panic，Value 为 panic 的值，见 JobStats.LastError
+type PanicError struct {
+	Value interface{}
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// SpecError spec 解析失败
+type SpecError struct {
+	// Spec 解析失败的 spec
+	Spec string
+	// Field 出错的字段，比如 "minute"、"day-of-week"，无法确定时为空
+	Field string
+	// Err 解析器返回的错误
+	Err error
+}
+
+func (e *SpecError) Error() string {
+	if e.Field != "" {
+		return fmt.Sprintf("cron: invalid spec %q: %s field: %v", e.Spec, e.Field, e.Err)
+	}
+	return fmt.Sprintf("cron: invalid spec %q: %v", e.Spec, e.Err)
+}
+
+func (e *SpecError) Unwrap() error {
+	return e.Err
+}
+
+// 六段式 spec 各字段的名称和取值个数，用于检查步长
+var specFields = []struct {
+	name string
+	span int
+}{
+	{"second", 60},
+	{"minute", 60},
+	{"hour", 24},
+	{"day-of-month", 31},
+	{"month", 12},
+	{"day-of-week", 7},
+}
+
+// Validate 检查所有已注册的任务，返回每个有问题任务的 *JobError，按 id 排序
+// 检查内容：
+// 调度能否计算出下一次执行时间（比如 2 月 30 日永远不会执行），AddDependentJob 和尚未触发的一次性任务除外；
+// HardTimeout 是否小于执行间隔；
+// */N 形式的步长能否整除字段范围，不能整除时跨越进位（比如每分钟的第 0 秒）的间隔会变短，
+// AddSecondJob(59) 生成的 */59 就是这种情况：每分钟第 0 秒和第 59 秒各执行一次
+func (s *Cron) Validate() []error {
+	var (
+		errs []error
+		now  = time.Now()
+	)
+	for _, st := range s.snapshot() {
+		for _, err := range s.validateEntry(st.ID, now) {
+			errs = append(errs, &JobError{ID: st.ID, Err: err})
+		}
+	}
+	return errs
+}
+
+func (s *Cron) validateEntry(id int, now time.Time) []error {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	e, ok := s.load(id)
+	if !ok {
+		return nil
+	}
+
+	var errs []error
+	if len(e.scheds) == 0 {
+		errs = append(errs, errors.New("no schedule"))
+	}
+	for i, sched := range e.scheds {
+		spec := e.specs[i]
+		switch sched := sched.(type) {
+		case *dependsSchedule:
+			// 由上游任务触发，本身没有触发时间
+			continue
+		case *onceSchedule:
+			// 尚未触发的一次性调度即使已经过期，也会在调度器运行时立即执行
+			if sched.pending() {
+				continue
+			}
+		}
+		next := peekNext(sched, now)
+		if next.IsZero() {
+			errs = append(errs, fmt.Errorf("spec %q: never fires", spec))
+			continue
+		}
+		if e.opt.HardTimeout > 0 {
+			if after := peekNext(sched, next); !after.IsZero() && e.opt.HardTimeout >= after.Sub(next) {
+				errs = append(errs, fmt.Errorf("spec %q: hard timeout %v is not shorter than interval %v",
+					spec, e.opt.HardTimeout, after.Sub(next)))
+			}
+		}
+		errs = append(errs, checkSteps(s.expand(spec))...)
+	}
+	return errs
+}
+
+// checkSteps 检查六段式 spec 中不能整除字段范围的步长，开头的 TZ= 或 CRON_TZ= 不参与检查
+func checkSteps(spec string) []error {
+	_, fields := splitTZ(spec)
+	if len(fields) != len(specFields) {
+		return nil
+	}
+	var errs []error
+	for i, field := range fields {
+		for _, part := range strings.Split(field, ",") {
+			j := strings.Index(part, "/")
+			if j < 0 {
+				continue
+			}
+			step, err := strconv.Atoi(part[j+1:])
+			if err != nil || step <= 1 {
+				continue
+			}
+			if span := specFields[i].span; span%step != 0 {
+				errs = append(errs, fmt.Errorf("spec %q: %s step %d does not divide %d, interval is uneven across rollover",
+					spec, specFields[i].name, step, span))
+			}
+		}
+	}
+	return errs
+}
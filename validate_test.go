@@ -0,0 +1,135 @@
+package cron
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// validateErrors 返回 Validate 对任务 id 报告的错误信息
+func validateErrors(c *Cron, id int) []string {
+	var out []string
+	for _, err := range c.Validate() {
+		var je *JobError
+		if errors.As(err, &je) && je.ID == id {
+			out = append(out, je.Err.Error())
+		}
+	}
+	return out
+}
+
+func TestValidateNeverFires(t *testing.T) {
+	c := NewCron(WithLogger(DiscardLogger))
+	id := c.AddJob("0 0 0 30 2 *", func() {})
+	if errs := validateErrors(c, id); len(errs) != 1 || !strings.Contains(errs[0], "never fires") {
+		t.Errorf("errors = %v, want never fires", errs)
+	}
+}
+
+func TestValidateAcceptsDependentJob(t *testing.T) {
+	c := NewCron(WithLogger(DiscardLogger))
+	up := c.AddJob("0 0 9 * * *", func() {})
+	dep := c.AddDependentJob(func() {}, []int{up})
+	if dep < 0 {
+		t.Fatal("AddDependentJob failed")
+	}
+	if errs := c.Validate(); len(errs) != 0 {
+		t.Errorf("Validate() = %v, want no errors", errs)
+	}
+}
+
+func TestValidateAcceptsPendingOnceJobs(t *testing.T) {
+	c := NewCron(WithLogger(DiscardLogger))
+	// 已经过期但尚未触发，调度器运行后会立即执行
+	c.AddAtJob(time.Now().Add(-time.Hour), func() {})
+	c.AddAtJob(time.Now().Add(time.Hour), func() {})
+	c.AddOnceJob(time.Minute, func() {})
+	c.AddFixedDelayJob(time.Minute, func() {})
+	if errs := c.Validate(); len(errs) != 0 {
+		t.Errorf("Validate() = %v, want no errors", errs)
+	}
+}
+
+func TestValidateUnevenSteps(t *testing.T) {
+	tests := []struct {
+		spec string
+		want string
+	}{
+		{"*/59 * * * * *", "second step 59 does not divide 60"},
+		{"0 */7 * * * *", "minute step 7 does not divide 60"},
+		{"0 0 */5 * * *", "hour step 5 does not divide 24"},
+		{"0 0,10-50/7 * * * *", "minute step 7 does not divide 60"},
+		{"CRON_TZ=Asia/Shanghai 0 */7 * * * *", "minute step 7 does not divide 60"},
+		{"TZ=UTC 0 0 */5 * * *", "hour step 5 does not divide 24"},
+	}
+	for _, tt := range tests {
+		c := NewCron(WithLogger(DiscardLogger))
+		id, err := c.AddJobE(tt.spec, func() {})
+		if err != nil {
+			t.Fatalf("%q: %v", tt.spec, err)
+		}
+		if errs := validateErrors(c, id); len(errs) != 1 || !strings.Contains(errs[0], tt.want) {
+			t.Errorf("%q: errors = %v, want %q", tt.spec, errs, tt.want)
+		}
+	}
+}
+
+func TestValidateEvenSteps(t *testing.T) {
+	c := NewCron(WithLogger(DiscardLogger))
+	for _, spec := range []string{
+		"*/15 * * * * *",
+		"0 */5 * * * *",
+		"0 0 */6 * * *",
+		"CRON_TZ=Asia/Shanghai 0 */10 * * * *",
+		"@every 7m",
+		"@daily",
+	} {
+		if _, err := c.AddJobE(spec, func() {}); err != nil {
+			t.Fatalf("%q: %v", spec, err)
+		}
+	}
+	if errs := c.Validate(); len(errs) != 0 {
+		t.Errorf("Validate() = %v, want no errors", errs)
+	}
+}
+
+func TestValidateUnevenStepsWithoutSeconds(t *testing.T) {
+	c := NewCron(WithoutSeconds(), WithLogger(DiscardLogger))
+	id, err := c.AddJobE("CRON_TZ=UTC */7 * * * *", func() {})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if errs := validateErrors(c, id); len(errs) != 1 || !strings.Contains(errs[0], "minute step 7") {
+		t.Errorf("errors = %v, want the minute step", errs)
+	}
+}
+
+func TestValidateHardTimeout(t *testing.T) {
+	c := NewCron(WithLogger(DiscardLogger))
+	long := c.AddJob("0 * * * * *", func() {}, WithHardTimeout(time.Minute))
+	short := c.AddJob("0 * * * * *", func() {}, WithHardTimeout(30*time.Second))
+	if errs := validateErrors(c, long); len(errs) != 1 || !strings.Contains(errs[0], "hard timeout 1m0s is not shorter than interval 1m0s") {
+		t.Errorf("errors = %v, want the hard timeout", errs)
+	}
+	if errs := validateErrors(c, short); len(errs) != 0 {
+		t.Errorf("errors = %v, want none", errs)
+	}
+}
+
+func TestValidateSortedByID(t *testing.T) {
+	c := NewCron(WithLogger(DiscardLogger))
+	for i := 0; i < 5; i++ {
+		c.AddJob("0 0 0 30 2 *", func() {})
+	}
+	errs := c.Validate()
+	if len(errs) != 5 {
+		t.Fatalf("%d errors, want 5", len(errs))
+	}
+	for i, err := range errs {
+		var je *JobError
+		if !errors.As(err, &je) || je.ID != i+1 {
+			t.Errorf("error %d = %v", i, err)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package cron
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ErrStalled 执行超过了 WithStallDetection 的阈值仍未结束
+var ErrStalled = errors.New("cron: job stalled")
+
+// 巡检间隔的下限，避免 threshold 很小时频繁扫描
+const minStallInterval = 10 * time.Millisecond
+
+type _StallDetection struct {
+	threshold time.Duration
+	handler   func(id int, runningFor time.Duration)
+}
+
+func (d _StallDetection) applyCron(opts *cronOptions) {
+	opts.StallThreshold = d.threshold
+	opts.StallHandler = d.handler
+}
+
+// WithStallDetection 调度器运行期间定期检查正在进行的执行，执行超过 threshold 仍未结束时调用 handler，
+// runningFor 为已经执行的时长；每次执行最多报告一次，handler 为 nil 时交给 Logger
+// 检查间隔为 threshold 的一半，只用于发现卡住的任务，不会中断执行，需要限制执行时间请使用 WithTimeout
+func WithStallDetection(threshold time.Duration, handler func(id int, runningFor time.Duration)) CronOption {
+	return _StallDetection{threshold: threshold, handler: handler}
+}
+
+// starts 任务进行中的执行的开始时间
+type starts struct {
+	mu   sync.Mutex
+	next int
+	runs map[int]*start
+}
+
+type start struct {
+	at       time.Time
+	reported bool
+}
+
+func newStarts() *starts {
+	return &starts{runs: make(map[int]*start)}
+}
+
+func (st *starts) add(at time.Time) int {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.next++
+	st.runs[st.next] = &start{at: at}
+	return st.next
+}
+
+func (st *starts) remove(key int) {
+	st.mu.Lock()
+	delete(st.runs, key)
+	st.mu.Unlock()
+}
+
+// stalled 返回开始时间早于 before 的执行的时长，report 为 true 时只返回尚未报告过的并将其标记为已报告
+func (st *starts) stalled(before time.Time, report bool) []time.Duration {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	var out []time.Duration
+	for _, r := range st.runs {
+		if !r.at.Before(before) || (report && r.reported) {
+			continue
+		}
+		if report {
+			r.reported = true
+		}
+		out = append(out, time.Since(r.at))
+	}
+	return out
+}
+
+// watch 定期检查卡住的执行，done 关闭时退出
+func (s *Cron) watch(done <-chan struct{}) {
+	interval := s.stallThreshold / 2
+	if interval < minStallInterval {
+		interval = minStallInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.checkStalled()
+		case <-done:
+			return
+		}
+	}
+}
+
+// checkStalled 报告超过 stallThreshold 的执行
+func (s *Cron) checkStalled() {
+	before := time.Now().Add(-s.stallThreshold)
+	type stall struct {
+		id int
+		d  time.Duration
+	}
+	var found []stall
+	s.lock.RLock()
+	s.entry.Range(func(key, value interface{}) bool {
+		for _, d := range value.(*entry).starts.stalled(before, true) {
+			found = append(found, stall{id: key.(int), d: d})
+		}
+		return true
+	})
+	s.lock.RUnlock()
+
+	for _, st := range found {
+		if s.stallHandler != nil {
+			s.stallHandler(st.id, st.d)
+			continue
+		}
+		l, kv := s.jobLogger(st.id)
+		l.Error(ErrStalled, "job stalled", append(kv, "running_for", st.d)...)
+	}
+}
+
+// HealthReport 调度器的健康状况，见 Healthz
+type HealthReport struct {
+	// Healthy 调度器在运行且没有卡住的执行
+	Healthy bool `json:"healthy"`
+	// Running 调度器是否在运行，见 IsRunning
+	Running bool `json:"running"`
+	// Jobs 注册的任务数量
+	Jobs int `json:"jobs"`
+	// Paused 已暂停的任务数量
+	Paused int `json:"paused"`
+	// Inflight 正在进行的执行次数
+	Inflight int `json:"inflight"`
+	// Stalled 有执行超过 WithStallDetection 阈值的任务 id，按 id 排序，未开启时为空
+	Stalled []int `json:"stalled,omitempty"`
+}
+
+// Healthz 汇总调度器当前的状态，可用于就绪探针
+func (s *Cron) Healthz() HealthReport {
+	report := HealthReport{Running: s.IsRunning()}
+	before := time.Now().Add(-s.stallThreshold)
+
+	s.lock.RLock()
+	s.entry.Range(func(key, value interface{}) bool {
+		e := value.(*entry)
+		report.Jobs++
+		if e.paused {
+			report.Paused++
+		}
+		report.Inflight += int(atomic.LoadInt64(&e.counters.inflight))
+		if s.stallThreshold > 0 && len(e.starts.stalled(before, false)) > 0 {
+			report.Stalled = append(report.Stalled, key.(int))
+		}
+		return true
+	})
+	s.lock.RUnlock()
+
+	sort.Ints(report.Stalled)
+	report.Healthy = report.Running && len(report.Stalled) == 0
+	return report
+}
@@ -0,0 +1,111 @@
+package cron
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type stallReport struct {
+	id         int
+	runningFor time.Duration
+}
+
+func TestStallDetectionReportsOnce(t *testing.T) {
+	stalls := make(chan stallReport, 4)
+	c := NewCron(WithStallDetection(20*time.Millisecond, func(id int, d time.Duration) {
+		stalls <- stallReport{id, d}
+	}), WithLogger(DiscardLogger))
+	c.Start()
+	defer c.Stop()
+	quick := c.AddJob("0 0 9 * * *", func() {})
+	id, started, release := blockingJob(t, c)
+	c.Call(quick)
+	c.CallAsync(id)
+	receive(t, started)
+
+	got := receive(t, stalls)
+	if got.id != id || got.runningFor < 20*time.Millisecond {
+		t.Errorf("stall = %+v, want job %d running for at least 20ms", got, id)
+	}
+	// 同一次执行只报告一次
+	time.Sleep(50 * time.Millisecond)
+	never(t, stalls)
+
+	// 执行结束后不再报告
+	release()
+	waitIdle(t, c, id)
+	never(t, stalls)
+}
+
+func TestStallDetectionLogsWithoutHandler(t *testing.T) {
+	logger := &errorLogger{}
+	c := NewCron(WithStallDetection(10*time.Millisecond, nil), WithLogger(logger))
+	c.Start()
+	defer c.Stop()
+	id, started, release := blockingJob(t, c)
+	c.CallAsync(id)
+	receive(t, started)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for atomic.LoadInt32(&logger.errors) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("stall was not logged")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(30 * time.Millisecond)
+	if n := atomic.LoadInt32(&logger.errors); n != 1 {
+		t.Errorf("%d errors logged, want 1", n)
+	}
+	release()
+}
+
+func TestHealthz(t *testing.T) {
+	c := NewCron(WithStallDetection(20*time.Millisecond, func(int, time.Duration) {}), WithLogger(DiscardLogger))
+	paused := c.AddJob("0 0 9 * * *", func() {})
+	c.PauseJob(paused)
+	id, started, release := blockingJob(t, c)
+
+	if report := c.Healthz(); report.Healthy || report.Running || report.Jobs != 2 || report.Paused != 1 {
+		t.Errorf("stopped scheduler: %+v", report)
+	}
+
+	c.Start()
+	defer c.Stop()
+	if report := c.Healthz(); !report.Healthy || !report.Running || report.Inflight != 0 || report.Stalled != nil {
+		t.Errorf("idle scheduler: %+v", report)
+	}
+
+	c.CallAsync(id)
+	receive(t, started)
+	if report := c.Healthz(); !report.Healthy || report.Inflight != 1 {
+		t.Errorf("running job: %+v", report)
+	}
+	time.Sleep(30 * time.Millisecond)
+	report := c.Healthz()
+	if report.Healthy || len(report.Stalled) != 1 || report.Stalled[0] != id {
+		t.Errorf("stalled job: %+v", report)
+	}
+
+	release()
+	waitIdle(t, c, id)
+	if report := c.Healthz(); !report.Healthy || report.Inflight != 0 || report.Stalled != nil {
+		t.Errorf("after the job finished: %+v", report)
+	}
+}
+
+func TestHealthzWithoutStallDetection(t *testing.T) {
+	c := NewCron(WithLogger(DiscardLogger))
+	c.Start()
+	defer c.Stop()
+	id, started, release := blockingJob(t, c)
+	c.CallAsync(id)
+	receive(t, started)
+	time.Sleep(20 * time.Millisecond)
+	// 未开启 WithStallDetection 时不判断卡住
+	if report := c.Healthz(); !report.Healthy || report.Stalled != nil || report.Inflight != 1 {
+		t.Errorf("report = %+v", report)
+	}
+	release()
+}